@@ -9,7 +9,7 @@ import (
 	"golang.org/x/oauth2"
 )
 
-//go:generate go tool mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ConnectionTimer,ChargeRater,Battery,BatteryController,BatterySocLimiter,Circuit,Dimmer,Tariff
+//go:generate go tool mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,VehiclePosition,ConnectionTimer,ChargeRater,Battery,BatteryController,BatteryPowerController,BatterySocLimiter,Circuit,Dimmer,Tariff
 
 // Meter provides total active power in W
 type Meter interface {
@@ -36,6 +36,11 @@ type PhasePowers interface {
 	Powers() (float64, float64, float64, error)
 }
 
+// MeterUpdater signals that new meter values have been pushed by the device
+type MeterUpdater interface {
+	Updated() <-chan struct{}
+}
+
 // Battery provides battery Soc in %
 type Battery interface {
 	Soc() (float64, error)
@@ -61,6 +66,12 @@ type MaxACPowerGetter interface {
 	MaxACPower() float64
 }
 
+// PowerLimiter provides continuous active power limitation of pv inverters
+type PowerLimiter interface {
+	// LimitPower sets the active power limit in % of rated power, 100 removes the limit
+	LimitPower(limit float64) error
+}
+
 // ChargeState provides current charging status
 type ChargeState interface {
 	Status() (ChargeStatus, error)
@@ -85,6 +96,12 @@ type BatteryController interface {
 	SetBatteryMode(BatteryMode) error
 }
 
+// BatteryPowerController optionally allows to control home battery power by setpoint in W,
+// positive for discharging and negative for charging. Control is returned to the battery using api.BatteryNormal mode.
+type BatteryPowerController interface {
+	SetBatteryPower(power float64) error
+}
+
 // Charger provides current charging status and enable/disable charging
 type Charger interface {
 	ChargeState
@@ -98,6 +115,12 @@ type ChargerEx interface {
 	MaxCurrentMillis(current float64) error
 }
 
+// Discharger provides bidirectional charging by discharging the vehicle
+type Discharger interface {
+	// Discharge sets the discharge power in W, 0 stops discharging
+	Discharge(power float64) error
+}
+
 // PhaseSwitcher provides 1p3p switching
 type PhaseSwitcher interface {
 	Phases1p3p(phases int) error
@@ -198,6 +221,17 @@ type Dimmer interface {
 	Dim(bool) error
 }
 
+// OffGridDetector signals off-grid (backup) operation of hybrid inverters
+type OffGridDetector interface {
+	OffGrid() (bool, error)
+}
+
+// SgReady provides control of SG-Ready operating states 1 (lock) to 4 (forced on)
+type SgReady interface {
+	SgReadyState() (int64, error)
+	SetSgReadyState(int64) error
+}
+
 // Curtailer provides EEG §9 curtailment
 type Curtailer interface {
 	Curtailed() (bool, error)
@@ -214,6 +248,11 @@ type Resurrector interface {
 	WakeUp() error
 }
 
+// Rebooter provides a remote restart of the device
+type Rebooter interface {
+	Reboot() error
+}
+
 // Tariff is a tariff capable of retrieving tariff rates
 type Tariff interface {
 	Rates() (Rates, error)
@@ -253,6 +292,7 @@ type CsvWriter interface {
 type CircuitMeasurements interface {
 	GetChargePower() float64
 	GetMaxPhaseCurrent() float64
+	GetPhaseCurrents() [3]float64
 }
 
 // CircuitLoad represents a loadpoint attached to a circuit
@@ -276,7 +316,9 @@ type Circuit interface {
 	SetMaxCurrent(float64)
 	Update([]CircuitLoad) error
 	ValidateCurrent(old, new float64) float64
+	ValidatePhaseCurrent(phases [3]bool, old, new float64) float64
 	ValidatePower(old, new float64) float64
+	GetRemainingPower() float64
 
 	// EnWG §14a - reduce demand/consumption
 	Dim(bool)
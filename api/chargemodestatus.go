@@ -5,7 +5,7 @@ import (
 	"strings"
 )
 
-// ChargeMode is the charge operation mode. Valid values are off, now, minpv and pv
+// ChargeMode is the charge operation mode. Valid values are off, now, minpv, pv and v2h
 type ChargeMode string
 
 // Charge modes
@@ -15,6 +15,7 @@ const (
 	ModeNow   ChargeMode = "now"
 	ModeMinPV ChargeMode = "minpv"
 	ModePV    ChargeMode = "pv"
+	ModeV2H   ChargeMode = "v2h"
 )
 
 // String implements Stringer
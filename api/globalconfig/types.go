@@ -52,6 +52,7 @@ type All struct {
 	Javascript      []Javascript
 	Go              []Go
 	Influx          Influx
+	Webhooks        []Webhook
 	EEBus           eebus.Config
 	HEMS            Hems
 	SHM             shm.Config
@@ -62,10 +63,19 @@ type All struct {
 	Vehicles        []config.Named
 	Tariffs         Tariffs
 	Site            map[string]any
+	Sites           []Site
 	Loadpoints      []config.Named
 	Circuits        []config.Named
 }
 
+// Site is an additional site with its own meters, circuit and loadpoints.
+// Tariffs not configured for the site are shared with the main site.
+type Site struct {
+	Name    string
+	Tariffs Tariffs
+	Other   map[string]any `mapstructure:",remain"`
+}
+
 type Javascript struct {
 	VM     string
 	Script string
@@ -142,6 +152,16 @@ func (c Influx) Redacted() any {
 	}
 }
 
+// Webhook is the outbound webhook configuration
+type Webhook struct {
+	URI      string        `json:"uri"`
+	Secret   string        `json:"secret"`
+	Keys     []string      `json:"keys"`
+	Events   []string      `json:"events"`
+	Debounce time.Duration `json:"debounce"`
+	Retries  int           `json:"retries"`
+}
+
 type DB struct {
 	Type string
 	Dsn  string
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/evcc-io/evcc/api (interfaces: Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ConnectionTimer,ChargeRater,Battery,BatteryController,BatterySocLimiter,Circuit,Dimmer,Tariff)
+// Source: github.com/evcc-io/evcc/api (interfaces: Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,VehiclePosition,ConnectionTimer,ChargeRater,Battery,BatteryController,BatteryPowerController,BatterySocLimiter,Circuit,Dimmer,Tariff)
 //
 // Generated by this command:
 //
-//	mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,ConnectionTimer,ChargeRater,Battery,BatteryController,BatterySocLimiter,Circuit,Dimmer,Tariff
+//	mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,CurrentLimiter,CurrentGetter,PhaseSwitcher,PhaseGetter,FeatureDescriber,Identifier,Meter,MeterEnergy,PhaseCurrents,Vehicle,VehiclePosition,ConnectionTimer,ChargeRater,Battery,BatteryController,BatteryPowerController,BatterySocLimiter,Circuit,Dimmer,Tariff
 //
 
 // Package api is a generated GoMock package.
@@ -638,6 +638,46 @@ func (mr *MockVehicleMockRecorder) Soc() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Soc", reflect.TypeOf((*MockVehicle)(nil).Soc))
 }
 
+// MockVehiclePosition is a mock of VehiclePosition interface.
+type MockVehiclePosition struct {
+	ctrl     *gomock.Controller
+	recorder *MockVehiclePositionMockRecorder
+	isgomock struct{}
+}
+
+// MockVehiclePositionMockRecorder is the mock recorder for MockVehiclePosition.
+type MockVehiclePositionMockRecorder struct {
+	mock *MockVehiclePosition
+}
+
+// NewMockVehiclePosition creates a new mock instance.
+func NewMockVehiclePosition(ctrl *gomock.Controller) *MockVehiclePosition {
+	mock := &MockVehiclePosition{ctrl: ctrl}
+	mock.recorder = &MockVehiclePositionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVehiclePosition) EXPECT() *MockVehiclePositionMockRecorder {
+	return m.recorder
+}
+
+// Position mocks base method.
+func (m *MockVehiclePosition) Position() (float64, float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Position")
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(float64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Position indicates an expected call of Position.
+func (mr *MockVehiclePositionMockRecorder) Position() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Position", reflect.TypeOf((*MockVehiclePosition)(nil).Position))
+}
+
 // MockConnectionTimer is a mock of ConnectionTimer interface.
 type MockConnectionTimer struct {
 	ctrl     *gomock.Controller
@@ -793,6 +833,44 @@ func (mr *MockBatteryControllerMockRecorder) SetBatteryMode(arg0 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBatteryMode", reflect.TypeOf((*MockBatteryController)(nil).SetBatteryMode), arg0)
 }
 
+// MockBatteryPowerController is a mock of BatteryPowerController interface.
+type MockBatteryPowerController struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatteryPowerControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockBatteryPowerControllerMockRecorder is the mock recorder for MockBatteryPowerController.
+type MockBatteryPowerControllerMockRecorder struct {
+	mock *MockBatteryPowerController
+}
+
+// NewMockBatteryPowerController creates a new mock instance.
+func NewMockBatteryPowerController(ctrl *gomock.Controller) *MockBatteryPowerController {
+	mock := &MockBatteryPowerController{ctrl: ctrl}
+	mock.recorder = &MockBatteryPowerControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatteryPowerController) EXPECT() *MockBatteryPowerControllerMockRecorder {
+	return m.recorder
+}
+
+// SetBatteryPower mocks base method.
+func (m *MockBatteryPowerController) SetBatteryPower(power float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBatteryPower", power)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBatteryPower indicates an expected call of SetBatteryPower.
+func (mr *MockBatteryPowerControllerMockRecorder) SetBatteryPower(power any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBatteryPower", reflect.TypeOf((*MockBatteryPowerController)(nil).SetBatteryPower), power)
+}
+
 // MockBatterySocLimiter is a mock of BatterySocLimiter interface.
 type MockBatterySocLimiter struct {
 	ctrl     *gomock.Controller
@@ -978,6 +1056,34 @@ func (mr *MockCircuitMockRecorder) GetParent() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParent", reflect.TypeOf((*MockCircuit)(nil).GetParent))
 }
 
+// GetPhaseCurrents mocks base method.
+func (m *MockCircuit) GetPhaseCurrents() [3]float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPhaseCurrents")
+	ret0, _ := ret[0].([3]float64)
+	return ret0
+}
+
+// GetPhaseCurrents indicates an expected call of GetPhaseCurrents.
+func (mr *MockCircuitMockRecorder) GetPhaseCurrents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPhaseCurrents", reflect.TypeOf((*MockCircuit)(nil).GetPhaseCurrents))
+}
+
+// GetRemainingPower mocks base method.
+func (m *MockCircuit) GetRemainingPower() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemainingPower")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetRemainingPower indicates an expected call of GetRemainingPower.
+func (mr *MockCircuitMockRecorder) GetRemainingPower() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemainingPower", reflect.TypeOf((*MockCircuit)(nil).GetRemainingPower))
+}
+
 // GetTitle mocks base method.
 func (m *MockCircuit) GetTitle() string {
 	m.ctrl.T.Helper()
@@ -1082,6 +1188,20 @@ func (mr *MockCircuitMockRecorder) ValidateCurrent(old, new any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateCurrent", reflect.TypeOf((*MockCircuit)(nil).ValidateCurrent), old, new)
 }
 
+// ValidatePhaseCurrent mocks base method.
+func (m *MockCircuit) ValidatePhaseCurrent(phases [3]bool, old, new float64) float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatePhaseCurrent", phases, old, new)
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// ValidatePhaseCurrent indicates an expected call of ValidatePhaseCurrent.
+func (mr *MockCircuitMockRecorder) ValidatePhaseCurrent(phases, old, new any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePhaseCurrent", reflect.TypeOf((*MockCircuit)(nil).ValidatePhaseCurrent), phases, old, new)
+}
+
 // ValidatePower mocks base method.
 func (m *MockCircuit) ValidatePower(old, new float64) float64 {
 	m.ctrl.T.Helper()
@@ -13,6 +13,27 @@ type RepeatingPlan struct {
 	Active   bool   `json:"active"`   // active flag
 }
 
+// DepartureGuarantee is a minimum soc or range guaranteed by a weekly recurring departure time
+type DepartureGuarantee struct {
+	Weekdays []int  `json:"weekdays"`        // 0-6 (Sunday-Saturday)
+	Time     string `json:"time"`            // HH:MM
+	Tz       string `json:"tz"`              // timezone in IANA format
+	Soc      int    `json:"soc,omitempty"`   // guaranteed soc
+	Range    int    `json:"range,omitempty"` // guaranteed range in km
+	Active   bool   `json:"active"`          // active flag
+}
+
+// ScheduledChange is a one-off or weekly recurring setting change
+type ScheduledChange struct {
+	At       time.Time `json:"at,omitzero"`        // one-off change time
+	Weekdays []int     `json:"weekdays,omitempty"` // 0-6 (Sunday-Saturday) for recurring changes
+	Time     string    `json:"time,omitempty"`     // HH:MM for recurring changes
+	Tz       string    `json:"tz,omitempty"`       // timezone in IANA format
+	Key      string    `json:"key"`                // setting, e.g. mode
+	Value    string    `json:"value"`              // setting value, e.g. pv
+	Active   bool      `json:"active"`             // active flag
+}
+
 type PlanStrategy struct {
 	Continuous   bool          `json:"continuous"`   // force continuous planning
 	Precondition time.Duration `json:"precondition"` // precondition duration in seconds
@@ -397,6 +397,13 @@ func (c *OCPP) Identify() (string, error) {
 	return c.conn.IdTag(), nil
 }
 
+var _ api.Rebooter = (*OCPP)(nil)
+
+// Reboot implements the api.Rebooter interface
+func (c *OCPP) Reboot() error {
+	return c.cp.ResetRequest(core.ResetTypeSoft)
+}
+
 var _ api.Diagnosis = (*OCPP)(nil)
 
 // Diagnose implements the api.Diagnosis interface
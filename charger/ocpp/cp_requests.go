@@ -120,3 +120,17 @@ func (cp *CP) GetConfigurationRequest() (*core.GetConfigurationConfirmation, err
 
 	return res, wait(err, rc)
 }
+
+func (cp *CP) ResetRequest(resetType core.ResetType) error {
+	rc := make(chan error, 1)
+
+	err := Instance().Reset(cp.id, func(request *core.ResetConfirmation, err error) {
+		if err == nil && request != nil && request.Status != core.ResetStatusAccepted {
+			err = errors.New(string(request.Status))
+		}
+
+		rc <- err
+	}, resetType)
+
+	return wait(err, rc)
+}
@@ -102,27 +102,42 @@ func NewSgReadyRelay(ctx context.Context, embed *embed, boost, dim api.Charger)
 			}
 			return boost.Enable(true)
 
+		case Force:
+			if dim == nil {
+				return api.ErrNotAvailable
+			}
+			if err := dim.Enable(true); err != nil {
+				return err
+			}
+			return boost.Enable(true)
+
 		default:
 			return fmt.Errorf("invalid sgready mode: %d", mode)
 		}
 	}
 
 	modeG := func() (int64, error) {
+		boosted, err := boost.Enabled()
+		if err != nil {
+			return 0, err
+		}
+
 		if dim != nil {
 			dimmed, err := dim.Enabled()
 			if err != nil {
 				return 0, err
 			}
+
+			// both contacts closed
+			if dimmed && boosted {
+				return Force, nil
+			}
+
 			if dimmed {
 				return Dim, nil
 			}
 		}
 
-		boosted, err := boost.Enabled()
-		if err != nil {
-			return 0, err
-		}
-
 		if boosted {
 			return Boost, nil
 		}
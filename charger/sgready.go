@@ -19,6 +19,7 @@ package charger
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/charger/measurement"
@@ -49,6 +50,7 @@ const (
 	Dim          // 1
 	Normal       // 2
 	Boost        // 3
+	Force        // 4
 )
 
 //go:generate go tool decorate -f decorateSgReady -b *SgReady -r api.Charger -t api.Meter,api.MeterEnergy,api.Battery,api.SocLimiter
@@ -88,6 +90,8 @@ func NewSgReadyFromConfig(ctx context.Context, other map[string]any) (api.Charge
 			log.DEBUG.Printf("set sgready mode: %s", "normal")
 		case Boost:
 			log.DEBUG.Printf("set sgready mode: %s", "boost")
+		case Force:
+			log.DEBUG.Printf("set sgready mode: %s", "force")
 		}
 		return modeSet(mode)
 	}
@@ -151,6 +155,7 @@ func (wb *SgReady) Status() (api.ChargeStatus, error) {
 		Dim:    api.StatusB,
 		Normal: api.StatusB,
 		Boost:  api.StatusC,
+		Force:  api.StatusC,
 	}
 	return status[mode], nil
 }
@@ -158,7 +163,7 @@ func (wb *SgReady) Status() (api.ChargeStatus, error) {
 // Enabled implements the api.Charger interface
 func (wb *SgReady) Enabled() (bool, error) {
 	mode, err := wb.getMode()
-	return mode == Boost, err
+	return mode >= Boost, err
 }
 
 // Enable implements the api.Charger interface
@@ -198,6 +203,28 @@ func (wb *SgReady) Dim(dim bool) error {
 	return nil
 }
 
+var _ api.SgReady = (*SgReady)(nil)
+
+// SgReadyState implements the api.SgReady interface
+func (wb *SgReady) SgReadyState() (int64, error) {
+	return wb.getMode()
+}
+
+// SetSgReadyState implements the api.SgReady interface
+func (wb *SgReady) SetSgReadyState(state int64) error {
+	if state < Dim || state > Force {
+		return fmt.Errorf("invalid sgready state: %d", state)
+	}
+
+	if err := wb.modeS(state); err != nil {
+		return err
+	}
+
+	wb.mode = state
+
+	return nil
+}
+
 // MaxCurrent implements the api.Charger interface
 func (wb *SgReady) MaxCurrent(current int64) error {
 	return wb.MaxCurrentMillis(float64(current))
@@ -0,0 +1,220 @@
+package charger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Simulator is a simulated bidirectional charger with connected vehicle
+type Simulator struct {
+	mu       sync.Mutex
+	log      *util.Logger
+	clock    clock.Clock
+	status   api.ChargeStatus
+	phases   int
+	capacity float64 // kWh
+
+	enabled   bool
+	current   float64
+	discharge float64
+	soc       float64
+	energy    float64 // kWh
+	updated   time.Time
+}
+
+func init() {
+	registry.Add("simulator", NewSimulatorFromConfig)
+}
+
+// NewSimulatorFromConfig creates a simulated charger from generic config
+func NewSimulatorFromConfig(other map[string]any) (api.Charger, error) {
+	cc := struct {
+		Status   api.ChargeStatus
+		Phases   int
+		Capacity float64
+		Soc      float64
+	}{
+		Status:   api.StatusB,
+		Phases:   3,
+		Capacity: 50,
+		Soc:      50,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	return NewSimulator(clock.New(), cc.Status, cc.Phases, cc.Capacity, cc.Soc), nil
+}
+
+// NewSimulator creates simulated charger
+func NewSimulator(clock clock.Clock, status api.ChargeStatus, phases int, capacity, soc float64) *Simulator {
+	return &Simulator{
+		log:      util.NewLogger("simulator"),
+		clock:    clock,
+		status:   status,
+		phases:   phases,
+		capacity: capacity,
+		soc:      soc,
+		updated:  clock.Now(),
+	}
+}
+
+// power returns the simulated power (no mutex)
+func (wb *Simulator) power() float64 {
+	switch {
+	case wb.status == api.StatusA:
+		return 0
+	case wb.discharge > 0:
+		return -wb.discharge
+	case wb.enabled && wb.soc < 100:
+		return wb.current * float64(wb.phases) * 230
+	default:
+		return 0
+	}
+}
+
+// update updates the vehicle soc from the power since last update (no mutex)
+func (wb *Simulator) update() {
+	now := wb.clock.Now()
+	energy := wb.power() * now.Sub(wb.updated).Hours() / 1e3 // kWh
+
+	if wb.capacity > 0 {
+		wb.soc = min(max(wb.soc+100*energy/wb.capacity, 0), 100)
+	}
+
+	wb.energy += max(energy, 0)
+
+	if wb.soc == 0 && wb.discharge > 0 {
+		wb.log.DEBUG.Println("vehicle empty, stop discharging")
+		wb.discharge = 0
+	}
+
+	wb.updated = now
+}
+
+// Status implements the api.Charger interface
+func (wb *Simulator) Status() (api.ChargeStatus, error) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	wb.update()
+
+	if wb.status == api.StatusA {
+		return api.StatusA, nil
+	}
+
+	if wb.power() != 0 {
+		return api.StatusC, nil
+	}
+
+	return api.StatusB, nil
+}
+
+// SetStatus connects or disconnects the simulated vehicle with given soc
+func (wb *Simulator) SetStatus(status api.ChargeStatus, soc float64) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	wb.update()
+
+	if wb.status == api.StatusA && status != api.StatusA {
+		wb.soc = soc
+		wb.energy = 0
+	}
+
+	wb.status = status
+	if status == api.StatusA {
+		wb.discharge = 0
+	}
+}
+
+// Enabled implements the api.Charger interface
+func (wb *Simulator) Enabled() (bool, error) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	return wb.enabled, nil
+}
+
+// Enable implements the api.Charger interface
+func (wb *Simulator) Enable(enable bool) error {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	wb.update()
+	wb.enabled = enable
+
+	return nil
+}
+
+// MaxCurrent implements the api.Charger interface
+func (wb *Simulator) MaxCurrent(current int64) error {
+	return wb.MaxCurrentMillis(float64(current))
+}
+
+var _ api.ChargerEx = (*Simulator)(nil)
+
+// MaxCurrentMillis implements the api.ChargerEx interface
+func (wb *Simulator) MaxCurrentMillis(current float64) error {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	wb.update()
+	wb.current = current
+
+	return nil
+}
+
+var _ api.Discharger = (*Simulator)(nil)
+
+// Discharge implements the api.Discharger interface
+func (wb *Simulator) Discharge(power float64) error {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	wb.update()
+	wb.discharge = max(power, 0)
+
+	return nil
+}
+
+var _ api.Meter = (*Simulator)(nil)
+
+// CurrentPower implements the api.Meter interface
+func (wb *Simulator) CurrentPower() (float64, error) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	wb.update()
+
+	return wb.power(), nil
+}
+
+var _ api.Battery = (*Simulator)(nil)
+
+// Soc implements the api.Battery interface
+func (wb *Simulator) Soc() (float64, error) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	wb.update()
+
+	return wb.soc, nil
+}
+
+var _ api.ChargeRater = (*Simulator)(nil)
+
+// ChargedEnergy implements the api.ChargeRater interface
+func (wb *Simulator) ChargedEnergy() (float64, error) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	wb.update()
+
+	return wb.energy, nil
+}
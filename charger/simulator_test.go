@@ -0,0 +1,52 @@
+package charger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulator(t *testing.T) {
+	clock := clock.NewMock()
+	wb := NewSimulator(clock, api.StatusB, 3, 10, 50)
+
+	status, err := wb.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusB, status)
+
+	// charging
+	require.NoError(t, wb.Enable(true))
+	require.NoError(t, wb.MaxCurrent(10))
+
+	f, err := wb.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 6900.0, f)
+
+	status, _ = wb.Status()
+	assert.Equal(t, api.StatusC, status)
+
+	// discharging takes precedence
+	require.NoError(t, wb.Discharge(5000))
+
+	f, _ = wb.CurrentPower()
+	assert.Equal(t, -5000.0, f)
+
+	clock.Add(30 * time.Minute)
+
+	f, _ = wb.Soc()
+	assert.Equal(t, 25.0, f)
+
+	// discharging stops when empty
+	clock.Add(time.Hour)
+
+	f, _ = wb.Soc()
+	assert.Equal(t, 0.0, f)
+
+	require.NoError(t, wb.Enable(false))
+	f, _ = wb.CurrentPower()
+	assert.Equal(t, 0.0, f)
+}
@@ -47,6 +47,8 @@ func init() {
 		reflect.TypeFor[api.BatteryCapacity](),
 		reflect.TypeFor[api.SocLimiter](),
 		reflect.TypeFor[api.BatteryController](),
+		reflect.TypeFor[api.BatteryPowerController](),
+		reflect.TypeFor[api.OffGridDetector](),
 		reflect.TypeFor[api.BatterySocLimiter](),
 		reflect.TypeFor[api.BatteryPowerLimiter](),
 		reflect.TypeFor[api.PhasePowers](),
@@ -62,6 +64,8 @@ func init() {
 		reflect.TypeFor[api.PhaseCurrents](),
 		reflect.TypeFor[api.PhaseVoltages](),
 		reflect.TypeFor[api.MaxACPowerGetter](),
+		reflect.TypeFor[api.PowerLimiter](),
+		reflect.TypeFor[api.MeterUpdater](),
 		reflect.TypeFor[api.Meter](),
 		reflect.TypeFor[api.CurrentGetter](),
 		reflect.TypeFor[api.Curtailer](),
@@ -104,6 +108,10 @@ func getTemplate(dtypes []reflect.Type, types map[string]typeStruct) *template.T
 
 func getTypeImport(t reflect.Type) string {
 	n := t.Name()
+	if n == "" {
+		// unnamed types like channels
+		return t.String()
+	}
 	if p := t.PkgPath(); p != "" {
 		if s := strings.Split(p, "github.com/evcc-io/evcc/"); len(s) == 2 {
 			return fmt.Sprintf("%s.%s", s[1], n)
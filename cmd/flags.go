@@ -15,6 +15,12 @@ const (
 	flagDemoMode            = "demo"
 	flagDemoModeDescription = "Enter demo mode. Disables auth, config ui and restart"
 
+	flagRecord            = "record"
+	flagRecordDescription = "Record plugin and http device traffic to file (debug only)"
+
+	flagReplay            = "replay"
+	flagReplayDescription = "Replay recorded plugin and http device traffic from file instead of accessing devices (debug only)"
+
 	flagIgnoreDatabase            = "ignore-db"
 	flagIgnoreDatabaseDescription = "Run command ignoring service database"
 
@@ -77,6 +83,18 @@ const (
 	flagTimeout            = "timeout"
 	flagTimeoutDescription = "Timeout"
 
+	flagVariants            = "variants"
+	flagVariantsDescription = "Strategy variants file (yaml)"
+	flagDuration            = "duration"
+	flagDurationDescription = "Simulated duration"
+	flagCapacity            = "capacity"
+	flagCapacityDescription = "Simulated vehicle capacity (kWh)"
+
+	flagBatteryCapacity            = "battery-capacity"
+	flagBatteryCapacityDescription = "Simulated home battery capacity (kWh)"
+	flagBatteryPower               = "battery-power"
+	flagBatteryPowerDescription    = "Simulated home battery power (W)"
+
 	flagDigits = "digits"
 	flagDelay  = "delay"
 	flagForce  = "force"
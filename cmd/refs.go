@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"iter"
 	"slices"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/evcc-io/evcc/api/globalconfig"
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/meter"
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/config"
@@ -40,30 +42,36 @@ func collectRefs(conf globalconfig.All) error {
 		return err
 	}
 
-	return collectLoadpointRefs(func(yield func(config.Named) bool) {
+	if err := collectLoadpointRefs(func(yield func(config.Named) bool) {
 		for _, cc := range configurable {
 			if !yield(cc.Named()) {
 				return
 			}
 		}
-	})
-}
+	}); err != nil {
+		return err
+	}
 
-func collectSiteRefs(conf globalconfig.All) error {
-	var refs struct {
-		Meters core.MetersConfig `mapstructure:"meters"` // Meter references
-		Other  map[string]any    `mapstructure:",remain"`
+	// circuits
+	if err := collectCircuitRefs(conf.Circuits); err != nil {
+		return err
 	}
 
-	if err := util.DecodeOther(conf.Site, &refs); err != nil {
+	// virtual meters
+	return collectVirtualMeterRefs(conf.Meters)
+}
+
+func collectSiteRefs(conf globalconfig.All) error {
+	if err := collectSiteMeterRefs(conf.Site); err != nil {
 		return err
 	}
 
-	references.meter = append(references.meter, refs.Meters.GridMeterRef)
-	references.meter = append(references.meter, refs.Meters.PVMetersRef...)
-	references.meter = append(references.meter, refs.Meters.BatteryMetersRef...)
-	references.meter = append(references.meter, refs.Meters.ExtMetersRef...)
-	references.meter = append(references.meter, refs.Meters.AuxMetersRef...)
+	// additional sites
+	for _, cc := range conf.Sites {
+		if err := collectSiteMeterRefs(cc.Other); err != nil {
+			return fmt.Errorf("site %s: %w", cc.Name, err)
+		}
+	}
 
 	// append devices from settings
 	if v, err := settings.String(keys.GridMeter); err == nil && v != "" {
@@ -79,6 +87,25 @@ func collectSiteRefs(conf globalconfig.All) error {
 	return nil
 }
 
+func collectSiteMeterRefs(other map[string]any) error {
+	var refs struct {
+		Meters core.MetersConfig `mapstructure:"meters"` // Meter references
+		Other  map[string]any    `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(other, &refs); err != nil {
+		return err
+	}
+
+	references.meter = append(references.meter, refs.Meters.GridMeterRef)
+	references.meter = append(references.meter, refs.Meters.PVMetersRef...)
+	references.meter = append(references.meter, refs.Meters.BatteryMetersRef...)
+	references.meter = append(references.meter, refs.Meters.ExtMetersRef...)
+	references.meter = append(references.meter, refs.Meters.AuxMetersRef...)
+
+	return nil
+}
+
 func collectTariffRefs() error {
 	// Load tariff device references from settings
 	if !settings.Exists(keys.TariffRefs) {
@@ -126,6 +153,96 @@ func collectLoadpointRefs(named iter.Seq[config.Named]) error {
 		references.charger = append(references.charger, refs.ChargerRef)
 		references.vehicle = append(references.vehicle, refs.VehicleRef)
 		references.circuit = append(references.circuit, refs.CircuitRef)
+
+		// thermal storage outside temperature forecast, tariffs are only filtered if references are stored in settings
+		var thermal struct {
+			Forecast string
+			Other    map[string]any `mapstructure:",remain"`
+		}
+
+		if err := util.DecodeOther(refs.Other["thermal"], &thermal); err != nil {
+			return err
+		}
+
+		if thermal.Forecast != "" && settings.Exists(keys.TariffRefs) {
+			references.tariff = append(references.tariff, thermal.Forecast)
+		}
+	}
+
+	return nil
+}
+
+// collectCircuitRefs adds chargers switching sheddable circuit loads
+func collectCircuitRefs(static []config.Named) error {
+	configurable, err := config.ConfigurationsByClass(templates.Circuit)
+	if err != nil {
+		return err
+	}
+
+	named := slices.Clone(static)
+	for _, conf := range configurable {
+		named = append(named, conf.Named())
+	}
+
+	for _, cc := range named {
+		var refs struct {
+			Loads []struct {
+				Charger string
+				Other   map[string]any `mapstructure:",remain"`
+			}
+			Other map[string]any `mapstructure:",remain"`
+		}
+
+		if err := util.DecodeOther(cc.Other, &refs); err != nil {
+			return err
+		}
+
+		for _, load := range refs.Loads {
+			references.charger = append(references.charger, load.Charger)
+		}
+	}
+
+	return nil
+}
+
+// collectVirtualMeterRefs adds meters referenced by referenced virtual meters
+func collectVirtualMeterRefs(static []config.Named) error {
+	configurable, err := config.ConfigurationsByClass(templates.Meter)
+	if err != nil {
+		return err
+	}
+
+	named := slices.Clone(static)
+	for _, conf := range configurable {
+		named = append(named, conf.Named())
+	}
+
+	// references grow while iterating to resolve nested virtual meters
+	for i := 0; i < len(references.meter); i++ {
+		idx := slices.IndexFunc(named, func(cc config.Named) bool {
+			return cc.Name == references.meter[i] && cc.Type == meter.Virtual
+		})
+		if idx < 0 {
+			continue
+		}
+
+		var refs struct {
+			Meters []struct {
+				Meter string
+				Other map[string]any `mapstructure:",remain"`
+			}
+			Other map[string]any `mapstructure:",remain"`
+		}
+
+		if err := util.DecodeOther(named[idx].Other, &refs); err != nil {
+			return err
+		}
+
+		for _, ref := range refs.Meters {
+			if !slices.Contains(references.meter, ref.Meter) {
+				references.meter = append(references.meter, ref.Meter)
+			}
+		}
 	}
 
 	return nil
@@ -87,6 +87,9 @@ func init() {
 	rootCmd.PersistentFlags().BoolP("help", "h", false, "Help")
 	rootCmd.PersistentFlags().Bool(flagHeaders, false, flagHeadersDescription)
 	rootCmd.PersistentFlags().Bool(flagIgnoreDatabase, false, flagIgnoreDatabaseDescription)
+	rootCmd.PersistentFlags().String(flagRecord, "", flagRecordDescription)
+	rootCmd.PersistentFlags().String(flagReplay, "", flagReplayDescription)
+	rootCmd.MarkFlagsMutuallyExclusive(flagRecord, flagReplay)
 
 	// config file options
 	rootCmd.PersistentFlags().StringP("log", "l", "info", "Log level (fatal, error, warn, info, debug, trace)")
@@ -267,9 +270,12 @@ func runRoot(cmd *cobra.Command, args []string) {
 	}
 
 	// setup site and loadpoints
-	var site *core.Site
+	var (
+		site  *core.Site
+		sites []*core.Site // additional sites
+	)
 	if err == nil {
-		site, err = configureSiteAndLoadpoints(&conf)
+		site, sites, err = configureSiteAndLoadpoints(&conf)
 	}
 
 	// setup influx
@@ -295,12 +301,29 @@ func runRoot(cmd *cobra.Command, args []string) {
 				keys.TariffSolar,
 				keys.ChargedEnergy,
 				keys.ChargeRemainingEnergy)
+			for _, s := range sites {
+				influx.AddSite(s.Name(), s)
+			}
+
 			go influx.Run(site, dedupe.Pipe(
 				pipe.NewDropper(append(ignoreLogs, ignoreEmpty, keys.Forecast)...).Pipe(tee.Attach()),
 			))
 		}
 	}
 
+	// setup webhooks
+	var webhooks []*server.Webhook
+	if err == nil {
+		webhooks, err = configureWebhooks(conf.Webhooks)
+		for _, webhook := range webhooks {
+			for _, s := range sites {
+				webhook.AddSite(s.Name(), s)
+			}
+
+			go webhook.Run(site, pipe.NewDropper(append(ignoreLogs, ignoreEmpty)...).Pipe(tee.Attach()))
+		}
+	}
+
 	// signal devices initialized
 	valueChan <- util.Param{Key: keys.StartupCompleted, Val: true}
 	// show onboarding UI
@@ -310,6 +333,11 @@ func runRoot(cmd *cobra.Command, args []string) {
 	if err == nil && conf.Mqtt.Broker != "" && conf.Mqtt.Topic != "" {
 		var mqtt *server.MQTT
 		mqtt, err = server.NewMQTT(strings.Trim(conf.Mqtt.Topic, "/"), site)
+		for _, s := range sites {
+			if err == nil {
+				err = mqtt.ListenSite(s.Name(), s)
+			}
+		}
 		if err == nil {
 			go mqtt.Run(site, pipe.NewDropper(append(ignoreMqtt, ignoreEmpty)...).Pipe(tee.Attach()))
 		}
@@ -352,6 +380,13 @@ func runRoot(cmd *cobra.Command, args []string) {
 		err = wrapErrorWithClass(ClassMessenger, err)
 	}
 
+	// publish loadpoint events to webhooks
+	if err == nil {
+		for _, webhook := range webhooks {
+			pushChan = webhook.Events(site, pushChan)
+		}
+	}
+
 	// publish initial settings
 	valueChan <- util.Param{Key: keys.EEBus, Val: globalconfig.ConfigStatus{
 		Config:     conf.EEBus.Redacted(),
@@ -447,6 +482,16 @@ func runRoot(cmd *cobra.Command, args []string) {
 		go func() {
 			site.Run(stopC, conf.Interval)
 		}()
+
+		// additional sites
+		for _, s := range sites {
+			s.DumpConfig()
+			s.Prepare(valueChan, pushChan)
+
+			httpd.RegisterNamedSiteHandlers(s.Name(), s)
+
+			go s.Run(stopC, conf.Interval)
+		}
 	}
 
 	if err != nil {
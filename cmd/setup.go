@@ -21,9 +21,11 @@ import (
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/core/circuit"
+	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	coresettings "github.com/evcc-io/evcc/core/settings"
+	corevehicle "github.com/evcc-io/evcc/core/vehicle"
 	"github.com/evcc-io/evcc/hems"
 	hemsapi "github.com/evcc-io/evcc/hems/hems"
 	"github.com/evcc-io/evcc/hems/shm"
@@ -43,6 +45,7 @@ import (
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/evcc-io/evcc/util/locale"
 	"github.com/evcc-io/evcc/util/machine"
+	"github.com/evcc-io/evcc/util/recorder"
 	"github.com/evcc-io/evcc/util/request"
 	_ "github.com/evcc-io/evcc/util/service"
 	"github.com/evcc-io/evcc/util/sponsor"
@@ -156,7 +159,8 @@ func isWritable(filePath string) bool {
 	return true
 }
 
-func configureCircuits(conf *[]config.Named) error {
+// configureCircuits configures the circuits, allowing one root circuit per site
+func configureCircuits(conf *[]config.Named, sites int) error {
 	// migrate settings
 	if settings.Exists(keys.Circuits) {
 		*conf = []config.Named{}
@@ -214,19 +218,19 @@ NEXT:
 		return fmt.Errorf("circuit is missing parent: %s", children[0].Name)
 	}
 
-	var rootFound bool
+	var roots int
 	for _, dev := range config.Circuits().Devices() {
 		c := dev.Instance()
 
 		if c.GetParent() == nil {
-			if rootFound {
+			if roots == sites {
 				return errors.New("cannot have multiple root circuits")
 			}
-			rootFound = true
+			roots++
 		}
 	}
 
-	if !rootFound && len(config.Circuits().Devices()) > 0 {
+	if roots == 0 && len(config.Circuits().Devices()) > 0 {
 		return errors.New("root circuit required")
 	}
 
@@ -284,9 +288,54 @@ func configurableInstance[T any](typ string, conf *config.Config, newFromConf ne
 	return err //nolint:govet
 }
 
+// virtualMeter is a deferred virtual meter creation
+type virtualMeter struct {
+	name   string
+	refs   []string
+	create func() error
+}
+
+// createVirtualMeters creates virtual meters after the meters they reference
+func createVirtualMeters(virtual []virtualMeter) error {
+	for len(virtual) > 0 {
+		var pending []virtualMeter
+
+		for _, vm := range virtual {
+			// defer until referenced virtual meters are created
+			if slices.ContainsFunc(vm.refs, func(ref string) bool {
+				return slices.ContainsFunc(virtual, func(other virtualMeter) bool {
+					return other.name == ref && other.name != vm.name
+				})
+			}) {
+				pending = append(pending, vm)
+				continue
+			}
+
+			if err := vm.create(); err != nil {
+				return err
+			}
+		}
+
+		if len(pending) == len(virtual) {
+			names := make([]string, 0, len(pending))
+			for _, vm := range pending {
+				names = append(names, vm.name)
+			}
+			return fmt.Errorf("circular virtual meter references: %s", strings.Join(names, ", "))
+		}
+
+		virtual = pending
+	}
+
+	return nil
+}
+
 func configureMeters(static []config.Named, names ...string) error {
 	var eg errgroup.Group
 
+	// virtual meters reference other meters and are created last
+	var virtual []virtualMeter
+
 	for i, cc := range static {
 		if cc.Name == "" {
 			return fmt.Errorf("cannot create meter %d: missing name", i+1)
@@ -301,9 +350,18 @@ func configureMeters(static []config.Named, names ...string) error {
 			log.WARN.Printf("create meter %d: %v", i+1, err)
 		}
 
-		eg.Go(func() error {
+		fun := func() error {
 			return staticInstance("meter", cc, meter.NewFromConfig, config.Meters())
-		})
+		}
+
+		if cc.Type == meter.Virtual {
+			// invalid configs fail on creation
+			refs, _ := meter.VirtualRefs(cc.Other)
+			virtual = append(virtual, virtualMeter{cc.Name, refs, fun})
+			continue
+		}
+
+		eg.Go(fun)
 	}
 
 	// append devices from database
@@ -313,19 +371,35 @@ func configureMeters(static []config.Named, names ...string) error {
 	}
 
 	for _, conf := range configurable {
-		eg.Go(func() error {
-			cc := conf.Named()
+		cc := conf.Named()
 
-			// always skip unreferenced db devices
-			if !slices.Contains(names, cc.Name) {
-				return nil
-			}
+		// always skip unreferenced db devices
+		if !slices.Contains(names, cc.Name) {
+			continue
+		}
 
+		fun := func() error {
 			return configurableInstance("meter", &conf, meter.NewFromConfig, config.Meters())
-		})
+		}
+
+		if cc.Type == meter.Virtual {
+			// invalid configs fail on creation
+			var refs []string
+			if props, err := customDevice(cc.Other); err == nil {
+				refs, _ = meter.VirtualRefs(props)
+			}
+			virtual = append(virtual, virtualMeter{cc.Name, refs, fun})
+			continue
+		}
+
+		eg.Go(fun)
 	}
 
-	return eg.Wait()
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	return createVirtualMeters(virtual)
 }
 
 func configureChargers(static []config.Named, names ...string) error {
@@ -510,6 +584,21 @@ func configureEnvironment(cmd *cobra.Command, conf *globalconfig.All) error {
 		request.LogHeaders = true
 	}
 
+	// record or replay device traffic
+	if file := cmd.Flag(flagRecord).Value.String(); file != "" {
+		if err := recorder.Record(file); err != nil {
+			return fmt.Errorf("record: %w", err)
+		}
+		log.WARN.Printf("recording device traffic to %s, recording may contain credentials", file)
+	}
+
+	if file := cmd.Flag(flagReplay).Value.String(); file != "" {
+		if err := recorder.Replay(file); err != nil {
+			return fmt.Errorf("replay: %w", err)
+		}
+		log.WARN.Printf("replaying device traffic from %s", file)
+	}
+
 	// setup persistence
 	err := wrapErrorWithClass(ClassDatabase, configureDatabase(conf.Database))
 
@@ -632,6 +721,22 @@ func configureInflux(conf *globalconfig.Influx) (*server.Influx, error) {
 	return influx, nil
 }
 
+// configureWebhooks configures outbound webhooks
+func configureWebhooks(conf []globalconfig.Webhook) ([]*server.Webhook, error) {
+	res := make([]*server.Webhook, 0, len(conf))
+
+	for i, cc := range conf {
+		webhook, err := server.NewWebhook(cc.URI, cc.Secret, cc.Keys, cc.Events, cc.Debounce, cc.Retries)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %d: %w", i+1, err)
+		}
+
+		res = append(res, webhook)
+	}
+
+	return res, nil
+}
+
 // setup mqtt
 func configureMqtt(conf *globalconfig.Mqtt) error {
 	// migrate settings
@@ -1105,7 +1210,7 @@ func configureDevices(conf globalconfig.All) error {
 		errs = append(errs, &ClassError{ClassVehicle, err})
 	}
 
-	if err := configureCircuits(&conf.Circuits); err != nil {
+	if err := configureCircuits(&conf.Circuits, 1+len(conf.Sites)); err != nil {
 		errs = append(errs, &ClassError{ClassCircuit, err})
 	}
 
@@ -1139,12 +1244,34 @@ func configureModbusProxy(conf *[]globalconfig.ModbusProxy) error {
 	return nil
 }
 
-func configureSiteAndLoadpoints(conf *globalconfig.All) (*core.Site, error) {
+// configureCoordinator creates the vehicle coordinator shared by all sites and keeps it in sync with the configured vehicles
+func configureCoordinator() *coordinator.Coordinator {
+	handler := config.Vehicles()
+	coord := coordinator.New(log, config.Instances(handler.Devices()))
+
+	handler.Subscribe(func(op config.Operation, dev config.Device[api.Vehicle]) {
+		switch op {
+		case config.OpAdd:
+			coord.Add(dev.Instance())
+
+		case config.OpDelete:
+			coord.Delete(dev.Instance())
+		}
+
+		// TODO remove vehicle from mqtt
+		corevehicle.Publish()
+	})
+
+	return coord
+}
+
+// configureSiteAndLoadpoints configures the main site and additional sites including their loadpoints
+func configureSiteAndLoadpoints(conf *globalconfig.All) (*core.Site, []*core.Site, error) {
 	// migrate settings
 	if settings.Exists(keys.Interval) {
 		d, err := settings.Int(keys.Interval)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		conf.Interval = time.Duration(d)
 	}
@@ -1168,25 +1295,99 @@ func configureSiteAndLoadpoints(conf *globalconfig.All) (*core.Site, error) {
 		return dev.Instance().(*core.Loadpoint)
 	})
 
-	site, err := configureSite(conf.Site, loadpoints, tariffs)
+	// loadpoints by site
+	siteLoadpoints := lo.GroupBy(loadpoints, func(lp *core.Loadpoint) string {
+		return lp.SiteRef
+	})
+
+	// vehicles are shared by all sites
+	coord := configureCoordinator()
+
+	sites, err := configureSites(conf.Sites, siteLoadpoints, tariffs, coord)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// root circuits of additional sites
+	circuits := lo.FilterMap(sites, func(s *core.Site, _ int) (string, bool) {
+		return s.CircuitRef, s.CircuitRef != ""
+	})
+
+	site, err := configureSite(conf.Site, siteLoadpoints[""], tariffs, coord, circuits...)
 	if err != nil {
 		errs = append(errs, err)
 	}
 
 	if len(errs) > 0 {
-		return site, joinErrors(errs...)
+		return site, sites, joinErrors(errs...)
 	}
 
 	if len(config.Circuits().Devices()) > 0 {
-		if err := validateCircuits(loadpoints); err != nil {
-			return site, &ClassError{ClassCircuit, err}
+		if err := validateCircuits(loadpoints, circuits...); err != nil {
+			return site, sites, &ClassError{ClassCircuit, err}
 		}
 	}
 
-	return site, nil
+	return site, sites, nil
 }
 
-func validateCircuits(loadpoints []*core.Loadpoint) error {
+// configureSites configures the additional sites. Tariffs not configured for a site are shared with the main site.
+func configureSites(conf []globalconfig.Site, loadpoints map[string][]*core.Loadpoint, shared *tariff.Tariffs, coord *coordinator.Coordinator) ([]*core.Site, error) {
+	var res []*core.Site
+
+	for _, cc := range conf {
+		if cc.Name == "" {
+			return res, errors.New("site: missing name")
+		}
+
+		if slices.ContainsFunc(res, func(s *core.Site) bool { return s.Name() == cc.Name }) {
+			return res, fmt.Errorf("site %s: duplicate name", cc.Name)
+		}
+
+		tariffs := *shared
+
+		var eg errgroup.Group
+		eg.Go(func() error { return configureTariff(cc.Tariffs.Grid, "", &tariffs.Grid) })
+		eg.Go(func() error { return configureTariff(cc.Tariffs.FeedIn, "", &tariffs.FeedIn) })
+		eg.Go(func() error { return configureTariff(cc.Tariffs.Co2, "", &tariffs.Co2) })
+		eg.Go(func() error { return configureTariff(cc.Tariffs.Planner, "", &tariffs.Planner) })
+		eg.Go(func() error { return configureSolarTariffs(cc.Tariffs.Solar, nil, &tariffs.Solar) })
+		if err := eg.Wait(); err != nil {
+			return res, &ClassError{ClassTariff, fmt.Errorf("site %s: %w", cc.Name, err)}
+		}
+
+		if cc.Tariffs.Currency != "" {
+			cur, err := currency.ParseISO(cc.Tariffs.Currency)
+			if err != nil {
+				return res, fmt.Errorf("site %s: %w", cc.Name, err)
+			}
+			tariffs.Currency = cur
+		}
+
+		site, err := core.NewNamedSiteFromConfig(cc.Name, cc.Other)
+		if err != nil {
+			return res, fmt.Errorf("site %s: %w", cc.Name, err)
+		}
+
+		if err := site.Boot(log, loadpoints[cc.Name], &tariffs, coord); err != nil {
+			return res, fmt.Errorf("site %s: failed booting site: %w", cc.Name, err)
+		}
+
+		res = append(res, site)
+	}
+
+	// loadpoints must reference a configured site
+	for name, lps := range loadpoints {
+		if name != "" && !slices.ContainsFunc(res, func(s *core.Site) bool { return s.Name() == name }) {
+			return res, &ClassError{ClassLoadpoint, fmt.Errorf("%s: site not found: %s", lps[0].GetTitle(), name)}
+		}
+	}
+
+	return res, nil
+}
+
+// validateCircuits validates the circuit hierarchy. Additional sites may each use a separate root circuit.
+func validateCircuits(loadpoints []*core.Loadpoint, siteCircuits ...string) error {
 	var hasRoot bool
 
 CONTINUE:
@@ -1194,6 +1395,10 @@ CONTINUE:
 		instance := dev.Instance()
 
 		isRoot := instance.GetParent() == nil
+		if isRoot && slices.Contains(siteCircuits, dev.Config().Name) {
+			continue CONTINUE
+		}
+
 		if isRoot {
 			if hasRoot {
 				return errors.New("multiple root circuits")
@@ -1217,16 +1422,37 @@ CONTINUE:
 		return errors.New("missing root circuit")
 	}
 
+	for _, name := range siteCircuits {
+		dev, err := config.Circuits().ByName(name)
+		if err != nil {
+			return err
+		}
+
+		if dev.Instance().GetParent() != nil {
+			return fmt.Errorf("site circuit %s is not a root circuit", name)
+		}
+	}
+
 	return nil
 }
 
-func configureSite(conf map[string]any, loadpoints []*core.Loadpoint, tariffs *tariff.Tariffs) (*core.Site, error) {
+func configureSite(conf map[string]any, loadpoints []*core.Loadpoint, tariffs *tariff.Tariffs, coord *coordinator.Coordinator, siteCircuits ...string) (*core.Site, error) {
 	site, err := core.NewSiteFromConfig(conf)
 	if err != nil {
 		return site, err
 	}
 
-	if err := site.Boot(log, loadpoints, tariffs); err != nil {
+	// use the root circuit not claimed by additional sites
+	if site.CircuitRef == "" && len(siteCircuits) > 0 {
+		for _, dev := range config.Circuits().Devices() {
+			if name := dev.Config().Name; dev.Instance().GetParent() == nil && !slices.Contains(siteCircuits, name) {
+				site.CircuitRef = name
+				break
+			}
+		}
+	}
+
+	if err := site.Boot(log, loadpoints, tariffs, coord); err != nil {
 		return site, fmt.Errorf("failed booting site: %w", err)
 	}
 
@@ -51,7 +51,7 @@ loadpoints:
 
 	suite.Require().NoError(viper.UnmarshalExact(&conf))
 
-	suite.Require().NoError(configureCircuits(&conf.Circuits))
+	suite.Require().NoError(configureCircuits(&conf.Circuits, 1))
 	suite.Require().Len(config.Circuits().Devices(), 2)
 	suite.Require().False(config.Circuits().Devices()[0].Instance().HasMeter())
 
@@ -82,7 +82,7 @@ loadpoints:
 
 	suite.Require().NoError(viper.UnmarshalExact(&conf))
 
-	suite.Require().NoError(configureCircuits(&conf.Circuits))
+	suite.Require().NoError(configureCircuits(&conf.Circuits, 1))
 	suite.Require().Len(config.Circuits().Devices(), 2)
 	suite.Require().False(config.Circuits().Devices()[0].Instance().HasMeter())
 
@@ -140,7 +140,7 @@ loadpoints:
 
 	suite.Require().NoError(viper.UnmarshalExact(&conf))
 
-	suite.Require().NoError(configureCircuits(&conf.Circuits))
+	suite.Require().NoError(configureCircuits(&conf.Circuits, 1))
 	suite.Require().Len(config.Circuits().Devices(), 1)
 
 	// mock charger
@@ -160,3 +160,34 @@ loadpoints:
 	// lp using root circuit is valid
 	suite.Require().NoError(validateCircuits(lps))
 }
+
+func (suite *circuitsTestSuite) TestSiteRootCircuits() {
+	var conf globalconfig.All
+	viper.SetConfigType("yaml")
+
+	suite.Require().NoError(viper.ReadConfig(strings.NewReader(`
+circuits:
+- name: main
+- name: garage
+- name: sub
+  parent: garage
+`)))
+
+	suite.Require().NoError(viper.UnmarshalExact(&conf))
+
+	suite.Require().NoError(configureCircuits(&conf.Circuits, 2))
+	suite.Require().Len(config.Circuits().Devices(), 3)
+
+	// multiple roots without sites
+	err := validateCircuits(nil)
+	suite.Require().Error(err)
+	suite.Require().Equal("multiple root circuits", err.Error())
+
+	// additional site using separate root
+	suite.Require().NoError(validateCircuits(nil, "garage"))
+
+	// additional site using non-root circuit
+	err = validateCircuits(nil, "garage", "sub")
+	suite.Require().Error(err)
+	suite.Require().Equal("site circuit sub is not a root circuit", err.Error())
+}
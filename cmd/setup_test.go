@@ -8,6 +8,8 @@ import (
 	"github.com/evcc-io/evcc/api/globalconfig"
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestYamlOff(t *testing.T) {
@@ -32,3 +34,29 @@ func TestYamlOff(t *testing.T) {
 		t.Errorf("expected `off`, got %s", lp.DefaultMode)
 	}
 }
+
+func TestCreateVirtualMeters(t *testing.T) {
+	var created []string
+
+	vm := func(name string, refs ...string) virtualMeter {
+		return virtualMeter{name, refs, func() error {
+			created = append(created, name)
+			return nil
+		}}
+	}
+
+	// house references virtual pv defined later
+	require.NoError(t, createVirtualMeters([]virtualMeter{
+		vm("house", "grid", "pv"),
+		vm("pv", "pv1", "pv2"),
+	}))
+	assert.Equal(t, []string{"pv", "house"}, created)
+
+	created = nil
+	require.Error(t, createVirtualMeters([]virtualMeter{
+		vm("a", "b"),
+		vm("b", "a"),
+		vm("c", "grid"),
+	}))
+	assert.Equal(t, []string{"c"}, created)
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/metrics"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/jinzhu/now"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v4"
+)
+
+// simulateCmd represents the simulate command
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate charging strategies on recorded history",
+	Long: `Simulate compares cost, self-consumption, CO2 and plan fulfillment of charging strategy variants.
+Recorded 15min household consumption and charging sessions of the most recent days are replayed through
+a site with simulated meters, chargers and vehicles, shifted by whole days into the period covered by the
+configured tariff and solar forecasts. Sessions are replayed if they start and end within the period.
+Missing consumption records are filled from the typical daily profile.
+
+Solar production is taken from the solar forecast. A home battery is simulated if its capacity is given.`,
+	Run: runSimulate,
+}
+
+// defaultVariants are the strategies compared if no variants file is given
+var defaultVariants = []core.SimulationVariant{
+	{Name: "now", Mode: api.ModeNow},
+	{Name: "minpv", Mode: api.ModeMinPV},
+	{Name: "pv", Mode: api.ModePV},
+	{Name: "pv+plan", Mode: api.ModePV, Plan: true},
+	{Name: "pv+plan continuous", Mode: api.ModePV, Plan: true, PlanStrategy: api.PlanStrategy{Continuous: true}},
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+	simulateCmd.Flags().String(flagVariants, "", flagVariantsDescription)
+	simulateCmd.Flags().Duration(flagDuration, 24*time.Hour, flagDurationDescription)
+	simulateCmd.Flags().Float64(flagCapacity, 60, flagCapacityDescription)
+	simulateCmd.Flags().Float64(flagBatteryCapacity, 0, flagBatteryCapacityDescription)
+	simulateCmd.Flags().Float64(flagBatteryPower, 5000, flagBatteryPowerDescription)
+}
+
+func runSimulate(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf, !cmd.Flag(flagIgnoreDatabase).Changed); err != nil {
+		fatal(err)
+	}
+
+	// setup environment
+	if err := configureEnvironment(cmd, &conf); err != nil {
+		fatal(err)
+	}
+
+	variants := defaultVariants
+	if file := cmd.Flag(flagVariants).Value.String(); file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			fatal(err)
+		}
+
+		if err := yaml.Unmarshal(b, &variants); err != nil {
+			fatal(fmt.Errorf("variants: %w", err))
+		}
+	}
+
+	tariffs, err := configureTariffs(&conf.Tariffs)
+	if err != nil {
+		fatal(err)
+	}
+
+	duration, _ := cmd.Flags().GetDuration(flagDuration)
+	start := time.Now().Truncate(tariff.SlotDuration)
+
+	// replay the most recent whole days preceding the simulated period
+	from := start.AddDate(0, 0, -int(math.Ceil(duration.Hours()/24)))
+	to := from.Add(duration)
+
+	household, err := metrics.Consumption(from, to)
+	if err != nil {
+		fatal(fmt.Errorf("household consumption: %w", err))
+	}
+
+	// typical consumption for missing records
+	profile, err := metrics.Profile(now.BeginningOfDay().AddDate(0, 0, -30))
+	if err != nil {
+		log.WARN.Printf("household profile: %v", err)
+	}
+
+	var history session.Sessions
+	if txn := db.Instance.Where("created >= ? AND finished > created AND finished < ?", from, to).Order("created").Find(&history); txn.Error != nil {
+		fatal(txn.Error)
+	}
+
+	sessions := core.SimulationSessions(history)
+	if len(sessions) == 0 {
+		fatal(errors.New("no charging sessions found"))
+	}
+
+	log.INFO.Printf("replaying %d consumption records and %d sessions from %s to %s", len(household), len(sessions),
+		from.Format(time.DateTime), to.Format(time.DateTime))
+
+	// keep simulated sessions, consumption and settings out of the database
+	if err := db.NewInstance("sqlite", ":memory:"); err != nil {
+		fatal(err)
+	}
+
+	sim := core.NewSimulation(log, tariffs, household, profile, sessions, start.Sub(from))
+	sim.Capacity, _ = cmd.Flags().GetFloat64(flagCapacity)
+	sim.BatteryCapacity, _ = cmd.Flags().GetFloat64(flagBatteryCapacity)
+	sim.BatteryPower, _ = cmd.Flags().GetFloat64(flagBatteryPower)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "Variant\tImport (kWh)\tExport (kWh)\tCharged (kWh)\tCost\tCO2 (kg)\tSelf-consumption\tPlan fulfillment")
+
+	for _, v := range variants {
+		res, err := sim.Run(start, duration, v)
+		if err != nil {
+			fatal(err)
+		}
+
+		fmt.Fprintf(tw, "%s\t%.1f\t%.1f\t%.1f\t%.2f\t%.1f\t%.0f%%\t%.0f%%\n", res.Variant,
+			res.GridImport, res.GridExport, res.Charged, res.Cost, res.Co2, 100*res.SelfConsumption, 100*res.PlanFulfillment)
+	}
+
+	tw.Flush()
+}
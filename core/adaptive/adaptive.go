@@ -0,0 +1,133 @@
+package adaptive
+
+import (
+	"math"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+const (
+	// Window is the duration over which power volatility is measured
+	Window = 15 * time.Minute
+
+	// DefaultMaxSwitches is the default number of charger switches per hour considered excessive
+	DefaultMaxSwitches = 6
+)
+
+// Decision is the result of adapting the pv mode thresholds
+type Decision struct {
+	Volatility float64 `json:"volatility"` // standard deviation of available power in W
+	Switches   int     `json:"switches"`   // charger switches during the last hour
+	Factor     float64 `json:"factor"`     // 0 (configured thresholds) .. 1 (configured bounds)
+	loadpoint.ThresholdsConfig
+}
+
+type sample struct {
+	time  time.Time
+	power float64
+}
+
+// Adapter adapts pv mode thresholds and delays to power volatility and charger switching frequency
+type Adapter struct {
+	clock    clock.Clock
+	config   loadpoint.AdaptiveConfig
+	samples  []sample
+	switches []time.Time
+}
+
+// New creates an adapter for the given bounds
+func New(clock clock.Clock, config loadpoint.AdaptiveConfig) *Adapter {
+	if config.MaxSwitches <= 0 {
+		config.MaxSwitches = DefaultMaxSwitches
+	}
+
+	return &Adapter{
+		clock:  clock,
+		config: config,
+	}
+}
+
+// Sample records the power available to the loadpoint
+func (a *Adapter) Sample(power float64) {
+	now := a.clock.Now()
+	a.samples = append(a.samples, sample{time: now, power: power})
+
+	for len(a.samples) > 0 && now.Sub(a.samples[0].time) > Window {
+		a.samples = a.samples[1:]
+	}
+}
+
+// Switched records a charger switch
+func (a *Adapter) Switched() {
+	a.switches = append(a.switches, a.clock.Now())
+}
+
+// volatility returns the standard deviation of the sampled power
+func (a *Adapter) volatility() float64 {
+	if len(a.samples) < 2 {
+		return 0
+	}
+
+	var sum, sq float64
+	for _, s := range a.samples {
+		sum += s.power
+	}
+
+	mean := sum / float64(len(a.samples))
+	for _, s := range a.samples {
+		sq += (s.power - mean) * (s.power - mean)
+	}
+
+	return math.Sqrt(sq / float64(len(a.samples)))
+}
+
+// recentSwitches returns the number of charger switches during the last hour
+func (a *Adapter) recentSwitches() int {
+	now := a.clock.Now()
+	for len(a.switches) > 0 && now.Sub(a.switches[0]) > time.Hour {
+		a.switches = a.switches[1:]
+	}
+	return len(a.switches)
+}
+
+// Adapt returns the thresholds adapted between configured values and bounds.
+// Volatility is measured relative to the loadpoint's minimum charging power.
+func (a *Adapter) Adapt(thresholds loadpoint.ThresholdsConfig, minPower float64) Decision {
+	res := Decision{
+		Volatility: a.volatility(),
+		Switches:   a.recentSwitches(),
+	}
+
+	if minPower > 0 {
+		res.Factor = min(res.Volatility/minPower, 1)
+	}
+	res.Factor = max(res.Factor, min(float64(res.Switches)/float64(a.config.MaxSwitches), 1))
+
+	delay := func(d time.Duration) time.Duration {
+		if a.config.MaxDelay <= d {
+			return d
+		}
+		return d + time.Duration(res.Factor*float64(a.config.MaxDelay-d)).Round(time.Second)
+	}
+
+	hysteresis := res.Factor * a.config.Hysteresis / 2
+
+	// zero enable threshold requires surplus for min charging power
+	enable := thresholds.Enable.Threshold
+	if enable == 0 && hysteresis > 0 {
+		enable = -minPower
+	}
+
+	res.Enable = loadpoint.ThresholdConfig{
+		Threshold: enable - hysteresis,
+		Delay:     delay(thresholds.Enable.Delay),
+	}
+	res.Disable = loadpoint.ThresholdConfig{
+		Threshold: thresholds.Disable.Threshold + hysteresis,
+		Delay:     delay(thresholds.Disable.Delay),
+	}
+
+	return res
+}
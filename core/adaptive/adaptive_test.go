@@ -0,0 +1,65 @@
+package adaptive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdapt(t *testing.T) {
+	clock := clock.NewMock()
+
+	a := New(clock, loadpoint.AdaptiveConfig{
+		MaxSwitches: 4,
+		MaxDelay:    10 * time.Minute,
+		Hysteresis:  1000,
+	})
+
+	thresholds := loadpoint.ThresholdsConfig{
+		Enable:  loadpoint.ThresholdConfig{Delay: time.Minute},
+		Disable: loadpoint.ThresholdConfig{Delay: 3 * time.Minute, Threshold: 200},
+	}
+
+	// steady power keeps configured thresholds
+	for range 10 {
+		a.Sample(2000)
+		clock.Add(30 * time.Second)
+	}
+
+	res := a.Adapt(thresholds, 1400)
+	assert.Equal(t, 0.0, res.Factor)
+	assert.Equal(t, thresholds, res.ThresholdsConfig)
+
+	// switching
+	a.Switched()
+	a.Switched()
+
+	res = a.Adapt(thresholds, 1400)
+	assert.Equal(t, 0.5, res.Factor)
+	assert.Equal(t, loadpoint.ThresholdsConfig{
+		Enable:  loadpoint.ThresholdConfig{Delay: 5*time.Minute + 30*time.Second, Threshold: -1650},
+		Disable: loadpoint.ThresholdConfig{Delay: 6*time.Minute + 30*time.Second, Threshold: 450},
+	}, res.ThresholdsConfig)
+
+	// switches expire after an hour
+	clock.Add(time.Hour + time.Minute)
+	res = a.Adapt(thresholds, 1400)
+	assert.Equal(t, 0, res.Switches)
+
+	// volatile power reaches bounds
+	for i := range 30 {
+		a.Sample(float64(i%2) * 3000)
+		clock.Add(30 * time.Second)
+	}
+
+	res = a.Adapt(thresholds, 1400)
+	assert.Equal(t, 1500.0, res.Volatility)
+	assert.Equal(t, 1.0, res.Factor)
+	assert.Equal(t, loadpoint.ThresholdsConfig{
+		Enable:  loadpoint.ThresholdConfig{Delay: 10 * time.Minute, Threshold: -1900},
+		Disable: loadpoint.ThresholdConfig{Delay: 10 * time.Minute, Threshold: 700},
+	}, res.ThresholdsConfig)
+}
@@ -0,0 +1,217 @@
+package arbitrage
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// DefaultEfficiency is the default battery round-trip efficiency
+const DefaultEfficiency = 0.8
+
+// Config is the battery arbitrage configuration
+type Config struct {
+	Power      float64 // charge and discharge power in W
+	Efficiency float64 // round-trip efficiency
+	CycleCost  float64 // battery wear cost per cycled kWh
+	ReserveSoc float64 // soc kept for the household
+}
+
+// Validate validates the configuration and applies defaults
+func (c *Config) Validate() error {
+	if c.Power <= 0 {
+		return errors.New("missing power")
+	}
+
+	if c.Efficiency == 0 {
+		c.Efficiency = DefaultEfficiency
+	}
+
+	if c.Efficiency < 0 || c.Efficiency > 1 {
+		return errors.New("invalid efficiency")
+	}
+
+	if c.ReserveSoc < 0 || c.ReserveSoc >= 100 {
+		return errors.New("invalid reserve soc")
+	}
+
+	return nil
+}
+
+// Action is the planned battery action of a window
+type Action string
+
+const (
+	Idle      Action = ""
+	Charge    Action = "charge"
+	Discharge Action = "discharge"
+)
+
+// Window is a planned arbitrage slot
+type Window struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Action Action    `json:"action"`
+	Price  float64   `json:"price"` // grid price when charging, feed-in price when discharging
+}
+
+type slot struct {
+	start, end   time.Time
+	grid, feedIn float64
+	energy       float64 // kWh charged or exported at configured power
+}
+
+type pair struct {
+	charge, discharge int // slot indexes, charge -1 for stored energy
+	profit            float64
+}
+
+// Plan computes charge and discharge windows from grid and feed-in rates. Stored energy above the reserve soc
+// may be exported, valued at the cheapest grid price of the horizon. Charging is limited by the usable capacity.
+// Round-trip losses are applied when discharging, i.e. exporting a slot's energy requires energy/efficiency stored.
+// Returns the windows and the expected profit.
+func Plan(cfg Config, grid, feedIn api.Rates, now time.Time, stored, usable float64) ([]Window, float64) {
+	var slots []slot
+
+	for _, r := range grid {
+		if !r.End.After(now) {
+			continue
+		}
+
+		f, err := feedIn.At(r.Start)
+		if err != nil {
+			continue
+		}
+
+		start := r.Start
+		if start.Before(now) {
+			start = now
+		}
+
+		slots = append(slots, slot{
+			start:  start,
+			end:    r.End,
+			grid:   r.Value,
+			feedIn: f.Value,
+			energy: cfg.Power / 1e3 * r.End.Sub(start).Hours(),
+		})
+	}
+
+	if len(slots) == 0 {
+		return nil, 0
+	}
+
+	minGrid := slices.MinFunc(slots, func(a, b slot) int {
+		switch {
+		case a.grid < b.grid:
+			return -1
+		case a.grid > b.grid:
+			return 1
+		}
+		return 0
+	}).grid
+
+	// candidate pairs of charge and discharge slots
+	var pairs []pair
+
+	for j, d := range slots {
+		value := d.feedIn*cfg.Efficiency - cfg.CycleCost
+
+		if p := value - minGrid; p > 0 && stored > 0 {
+			pairs = append(pairs, pair{charge: -1, discharge: j, profit: p})
+		}
+
+		for i := range j {
+			if p := value - slots[i].grid; p > 0 {
+				pairs = append(pairs, pair{charge: i, discharge: j, profit: p})
+			}
+		}
+	}
+
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		switch {
+		case a.profit > b.profit:
+			return -1
+		case a.profit < b.profit:
+			return 1
+		}
+		return 0
+	})
+
+	// energy level change after each slot
+	delta := make([]float64, len(slots))
+	action := make([]Action, len(slots))
+
+	feasible := func() bool {
+		level := stored
+		for _, d := range delta {
+			level += d
+			if level < -1e-9 || level > usable+1e-9 {
+				return false
+			}
+		}
+		return true
+	}
+
+	var profit float64
+
+	for _, p := range pairs {
+		if action[p.discharge] != Idle || p.charge >= 0 && action[p.charge] != Idle {
+			continue
+		}
+
+		// stored energy required for exporting at full power
+		energy := slots[p.discharge].energy / cfg.Efficiency
+		if p.charge >= 0 {
+			energy = min(energy, slots[p.charge].energy)
+			delta[p.charge] += energy
+		} else {
+			energy = min(energy, stored)
+		}
+		delta[p.discharge] -= energy
+
+		if !feasible() {
+			if p.charge >= 0 {
+				delta[p.charge] -= energy
+			}
+			delta[p.discharge] += energy
+			continue
+		}
+
+		if p.charge >= 0 {
+			action[p.charge] = Charge
+		}
+		action[p.discharge] = Discharge
+
+		profit += energy * p.profit
+	}
+
+	var res []Window
+
+	for i, s := range slots {
+		if action[i] == Idle {
+			continue
+		}
+
+		w := Window{Start: s.start, End: s.end, Action: action[i], Price: s.grid}
+		if action[i] == Discharge {
+			w.Price = s.feedIn
+		}
+
+		res = append(res, w)
+	}
+
+	return res, profit
+}
+
+// At returns the planned action for the given time
+func At(windows []Window, ts time.Time) Action {
+	for _, w := range windows {
+		if !ts.Before(w.Start) && ts.Before(w.End) {
+			return w.Action
+		}
+	}
+	return Idle
+}
@@ -0,0 +1,77 @@
+package arbitrage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rates(start time.Time, values ...float64) api.Rates {
+	res := make(api.Rates, 0, len(values))
+	for i, v := range values {
+		res = append(res, api.Rate{
+			Start: start.Add(time.Duration(i) * time.Hour),
+			End:   start.Add(time.Duration(i+1) * time.Hour),
+			Value: v,
+		})
+	}
+	return res
+}
+
+func TestPlan(t *testing.T) {
+	cfg := Config{Power: 2000, CycleCost: 0.02}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, DefaultEfficiency, cfg.Efficiency)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	grid := rates(now, 0.30, 0.10, 0.12, 0.35, 0.40)
+	feedIn := rates(now, 0.05, 0.05, 0.05, 0.30, 0.45)
+
+	// empty battery, room for one hour of charging
+	res, profit := Plan(cfg, grid, feedIn, now, 0, 2)
+	assert.Equal(t, []Window{
+		{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour), Action: Charge, Price: 0.10},
+		{Start: now.Add(4 * time.Hour), End: now.Add(5 * time.Hour), Action: Discharge, Price: 0.45},
+	}, res)
+	assert.InDelta(t, 2*(0.45*0.8-0.02-0.10), profit, 1e-9)
+
+	// room for two hours of charging
+	res, _ = Plan(cfg, grid, feedIn, now, 0, 4)
+	assert.Equal(t, []Action{Charge, Charge, Discharge, Discharge}, actions(res))
+
+	// full battery exports stored energy at peak
+	res, _ = Plan(cfg, grid, feedIn, now, 2, 2)
+	assert.Equal(t, []Action{Discharge}, actions(res))
+
+	// round-trip losses, stored energy insufficient for exporting two hours at full power
+	res, _ = Plan(cfg, grid, feedIn, now, 4, 4)
+	assert.Equal(t, []Action{Discharge}, actions(res))
+
+	// stored energy exported at peak, additional energy charged at low price
+	res, _ = Plan(cfg, grid, feedIn, now, 2, 4)
+	assert.Equal(t, []Action{Charge, Discharge, Discharge}, actions(res))
+	assert.Equal(t, Discharge, At(res, now.Add(4*time.Hour+time.Minute)))
+	assert.Equal(t, Idle, At(res, now))
+
+	// no spread
+	res, profit = Plan(cfg, grid, rates(now, 0.05, 0.05, 0.05, 0.05, 0.05), now, 2, 4)
+	assert.Empty(t, res)
+	assert.Zero(t, profit)
+}
+
+func TestValidate(t *testing.T) {
+	assert.Error(t, (&Config{}).Validate())
+	assert.Error(t, (&Config{Power: 1000, Efficiency: 1.2}).Validate())
+	assert.Error(t, (&Config{Power: 1000, ReserveSoc: 100}).Validate())
+}
+
+func actions(windows []Window) []Action {
+	res := make([]Action, 0, len(windows))
+	for _, w := range windows {
+		res = append(res, w.Action)
+	}
+	return res
+}
@@ -0,0 +1,145 @@
+package batterystats
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	maxGap      = 15 * time.Minute // updates further apart are not integrated
+	idlePower   = 50               // W, power below is considered idle
+	minSocSwing = 30               // %, soc change required for a capacity estimate
+	minCycles   = 1                // equivalent full cycles required for an efficiency estimate
+	smoothing   = 0.2              // weight of a new capacity estimate
+)
+
+// Stats is the cycle and degradation accounting of a single battery
+type Stats struct {
+	Charged    float64  `json:"charged"`              // charged energy in kWh
+	Discharged float64  `json:"discharged"`           // discharged energy in kWh
+	Cycles     float64  `json:"cycles"`               // equivalent full cycles
+	Efficiency *float64 `json:"efficiency,omitempty"` // round-trip efficiency 0..1
+	Capacity   *float64 `json:"capacity,omitempty"`   // estimated capacity in kWh
+	Health     *float64 `json:"health,omitempty"`     // estimated capacity relative to nominal capacity in %
+
+	StartSoc *float64 `json:"startSoc,omitempty"` // soc when accounting started
+
+	// capacity estimation segment of continuous charging or discharging
+	SegmentSoc    *float64 `json:"segmentSoc,omitempty"`
+	SegmentEnergy float64  `json:"segmentEnergy,omitempty"` // net charged energy in kWh
+
+	updated   time.Time
+	direction int // 1 charging, -1 discharging, 0 idle
+}
+
+// Update accounts for the battery power since the last update. Power is positive for discharging.
+// Soc and nominal capacity in kWh are optional and improve the estimates.
+func (s *Stats) Update(now time.Time, power float64, soc *float64, nominal float64) {
+	defer func() { s.updated = now }()
+
+	if s.StartSoc == nil && soc != nil {
+		s.StartSoc = new(*soc)
+	}
+
+	if s.updated.IsZero() || now.Sub(s.updated) > maxGap {
+		s.restartSegment(soc)
+		return
+	}
+
+	energy := power / 1e3 * now.Sub(s.updated).Hours()
+
+	capacity := nominal
+	if s.Capacity != nil {
+		capacity = *s.Capacity
+	}
+
+	if energy > 0 {
+		s.Discharged += energy
+		if capacity > 0 {
+			s.Cycles += energy / capacity
+		}
+	} else {
+		s.Charged -= energy
+	}
+
+	s.updateEfficiency(soc, capacity)
+
+	if soc == nil {
+		return
+	}
+
+	// restart capacity segment on direction change
+	var direction int
+	switch {
+	case power > idlePower:
+		direction = -1
+	case power < -idlePower:
+		direction = 1
+	}
+
+	if direction != 0 && s.direction != 0 && direction != s.direction {
+		s.direction = direction
+		s.restartSegment(soc)
+		return
+	}
+
+	if direction != 0 {
+		s.direction = direction
+	}
+
+	s.SegmentEnergy -= energy
+	s.updateCapacity(*soc, nominal)
+}
+
+// restartSegment starts a new capacity estimation segment
+func (s *Stats) restartSegment(soc *float64) {
+	s.SegmentSoc = nil
+	if soc != nil {
+		s.SegmentSoc = new(*soc)
+	}
+	s.SegmentEnergy = 0
+}
+
+// updateCapacity estimates the capacity once the soc has changed sufficiently within the segment
+func (s *Stats) updateCapacity(soc, nominal float64) {
+	if s.SegmentSoc == nil {
+		s.SegmentSoc = new(soc)
+		s.SegmentEnergy = 0
+		return
+	}
+
+	swing := soc - *s.SegmentSoc
+	if math.Abs(swing) < minSocSwing || s.SegmentEnergy*swing <= 0 {
+		return
+	}
+
+	estimate := s.SegmentEnergy / swing * 100
+
+	if s.Capacity != nil {
+		estimate = (1-smoothing)**s.Capacity + smoothing*estimate
+	}
+
+	s.Capacity = new(estimate)
+
+	if nominal > 0 {
+		s.Health = new(estimate / nominal * 100)
+	}
+
+	s.restartSegment(&soc)
+}
+
+// updateEfficiency estimates the round-trip efficiency corrected by the stored energy change
+func (s *Stats) updateEfficiency(soc *float64, capacity float64) {
+	if s.Cycles < minCycles || s.Charged <= 0 {
+		return
+	}
+
+	var stored float64
+	if soc != nil && s.StartSoc != nil && capacity > 0 {
+		stored = (*soc - *s.StartSoc) / 100 * capacity
+	}
+
+	if eff := (s.Discharged + stored) / s.Charged; eff > 0 && eff <= 1 {
+		s.Efficiency = new(eff)
+	}
+}
@@ -0,0 +1,83 @@
+package batterystats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsEnergyAndCycles(t *testing.T) {
+	var s Stats
+	now := time.Now()
+
+	// first update only initializes
+	s.Update(now, -2000, nil, 10)
+	assert.Zero(t, s.Charged)
+
+	// charge 2kWh
+	for range 6 {
+		now = now.Add(10 * time.Minute)
+		s.Update(now, -2000, nil, 10)
+	}
+	assert.InDelta(t, 2, s.Charged, 1e-9)
+
+	// discharge 5kWh
+	for range 5 {
+		now = now.Add(10 * time.Minute)
+		s.Update(now, 6000, nil, 10)
+	}
+	assert.InDelta(t, 5, s.Discharged, 1e-9)
+	assert.InDelta(t, 0.5, s.Cycles, 1e-9)
+
+	// gap is not integrated
+	now = now.Add(time.Hour)
+	s.Update(now, 6000, nil, 10)
+	assert.InDelta(t, 5, s.Discharged, 1e-9)
+}
+
+func TestStatsCapacity(t *testing.T) {
+	var s Stats
+	now := time.Now()
+	soc := 20.0
+
+	// charge 8kWh battery with 4kW from 20% to 70%
+	s.Update(now, -4000, &soc, 10)
+	for range 6 {
+		now = now.Add(10 * time.Minute)
+		soc += 50.0 / 6
+		s.Update(now, -4000, &soc, 10)
+	}
+
+	require.NotNil(t, s.Capacity)
+	assert.InDelta(t, 8, *s.Capacity, 1e-6)
+	require.NotNil(t, s.Health)
+	assert.InDelta(t, 80, *s.Health, 1e-6)
+
+	// direction change restarts segment
+	now = now.Add(10 * time.Minute)
+	soc -= 5
+	s.Update(now, 2400, &soc, 10)
+	assert.Equal(t, soc, *s.SegmentSoc)
+	assert.Zero(t, s.SegmentEnergy)
+}
+
+func TestStatsEfficiency(t *testing.T) {
+	var s Stats
+	now := time.Now()
+	soc := 50.0
+
+	s.Update(now, 0, &soc, 10)
+
+	// charge 12.5kWh, discharge 10kWh at unchanged soc
+	for _, power := range []float64{-12500, 10000} {
+		for range 4 {
+			now = now.Add(15 * time.Minute)
+			s.Update(now, power, &soc, 10)
+		}
+	}
+
+	require.NotNil(t, s.Efficiency)
+	assert.InDelta(t, 0.8, *s.Efficiency, 1e-9)
+}
@@ -8,8 +8,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/plugin"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/config"
@@ -18,10 +20,14 @@ import (
 
 var _ api.Circuit = (*Circuit)(nil)
 
+// tripMargin is the minimum duration an overload must remain tolerable by the trip curve
+const tripMargin = time.Minute
+
 // the circuit instances to control the load
 type Circuit struct {
-	mu  sync.RWMutex
-	log *util.Logger
+	mu    sync.RWMutex
+	log   *util.Logger
+	clock clock.Clock
 
 	title    string
 	parent   api.Circuit   // parent circuit
@@ -34,11 +40,23 @@ type Circuit struct {
 	getMaxCurrent func() (float64, error) // dynamic max allowed current
 	getMaxPower   func() (float64, error) // dynamic max allowed power
 
+	maxPhaseCurrents [3]float64 // max allowed current per phase
+	tripCurve        *TripCurve // tolerated overload
+
+	loadsMu      sync.Mutex    // serializes shedding and restoring on shutdown
+	loads        []*Load       // sheddable loads in shedding order
+	hysteresis   float64       // power headroom required for restoring shed loads
+	restoreDelay time.Duration // minimum time a load remains shed
+
 	current   float64
+	currents  [3]float64
 	power     float64
 	dimmed    bool
 	curtailed bool
 
+	reservedPower    float64    // power granted to loads since last update
+	reservedCurrents [3]float64 // current per phase granted to loads since last update
+
 	currentUpdated time.Time
 	powerUpdated   time.Time
 }
@@ -54,8 +72,17 @@ func NewFromConfig(ctx context.Context, log *util.Logger, other map[string]any)
 		GetMaxCurrent *plugin.Config // dynamic max allowed current
 		GetMaxPower   *plugin.Config // dynamic max allowed power
 		Timeout       time.Duration  // timeout between meter updates
+
+		MaxPhaseCurrents []float64   // max allowed current per phase
+		TripCurve        []TripPoint // tolerated overload
+
+		Loads        []LoadConfig  // sheddable loads
+		Hysteresis   float64       // power headroom required for restoring shed loads
+		RestoreDelay time.Duration // minimum time a load remains shed
 	}{
-		Timeout: time.Minute,
+		Timeout:      time.Minute,
+		Hysteresis:   DefaultHysteresis,
+		RestoreDelay: DefaultRestoreDelay,
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
@@ -89,6 +116,36 @@ func NewFromConfig(ctx context.Context, log *util.Logger, other map[string]any)
 		return nil, err
 	}
 
+	if len(cc.MaxPhaseCurrents) > 0 {
+		if err := circuit.setMaxPhaseCurrents(cc.MaxPhaseCurrents); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cc.TripCurve) > 0 {
+		if circuit.tripCurve, err = NewTripCurve(cc.TripCurve); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cc.Loads) > 0 {
+		var loads []*Load
+		for _, lc := range cc.Loads {
+			load, err := NewLoad(lc)
+			if err != nil {
+				return nil, fmt.Errorf("load: %w", err)
+			}
+			loads = append(loads, load)
+		}
+
+		if err := circuit.setLoads(loads, cc.Hysteresis, cc.RestoreDelay); err != nil {
+			return nil, err
+		}
+
+		// shed loads would remain switched off after restart
+		shutdown.Register(circuit.restoreLoads)
+	}
+
 	if cc.ParentRef != "" {
 		dev, err := config.Circuits().ByName(cc.ParentRef)
 		if err != nil {
@@ -108,6 +165,7 @@ func NewFromConfig(ctx context.Context, log *util.Logger, other map[string]any)
 func New(log *util.Logger, title string, maxCurrent, maxPower float64, meter api.Meter, timeout time.Duration) (*Circuit, error) {
 	c := &Circuit{
 		log:        log,
+		clock:      clock.New(),
 		title:      title,
 		maxCurrent: maxCurrent,
 		maxPower:   maxPower,
@@ -229,6 +287,40 @@ func (c *Circuit) SetMaxCurrent(current float64) {
 	c.maxCurrent = current
 }
 
+// setMaxPhaseCurrents sets the max current per phase, limited by the max current
+func (c *Circuit) setMaxPhaseCurrents(currents []float64) error {
+	if len(currents) != 3 {
+		return errors.New("max phase currents must be given for all three phases")
+	}
+
+	if c.meter != nil && !api.HasCap[api.PhaseCurrents](c.meter) {
+		return errors.New("meter does not support phase currents")
+	}
+
+	c.maxPhaseCurrents = [3]float64(currents)
+
+	return nil
+}
+
+// phaseLimits returns the max allowed current per phase, 0 if not limited
+func (c *Circuit) phaseLimits() [3]float64 {
+	maxCurrent := c.GetMaxCurrent()
+
+	var res [3]float64
+	for i, limit := range c.maxPhaseCurrents {
+		switch {
+		case limit == 0:
+			res[i] = maxCurrent
+		case maxCurrent == 0:
+			res[i] = limit
+		default:
+			res[i] = min(limit, maxCurrent)
+		}
+	}
+
+	return res
+}
+
 // RegisterChild registers child circuit
 func (c *Circuit) RegisterChild(child api.Circuit) {
 	c.children = append(c.children, child)
@@ -236,7 +328,7 @@ func (c *Circuit) RegisterChild(child api.Circuit) {
 
 func (c *Circuit) updateLoadpoints(loadpoints []api.CircuitLoad) {
 	c.power = 0
-	c.current = 0
+	c.currents = [3]float64{}
 
 	for _, lp := range loadpoints {
 		if lp.GetCircuit() != c {
@@ -244,7 +336,33 @@ func (c *Circuit) updateLoadpoints(loadpoints []api.CircuitLoad) {
 		}
 
 		c.power += lp.GetChargePower()
-		c.current += lp.GetMaxPhaseCurrent()
+		c.addCurrents(lp.GetPhaseCurrents())
+	}
+}
+
+func (c *Circuit) addCurrents(currents [3]float64) {
+	for i := range c.currents {
+		c.currents[i] += currents[i]
+	}
+	c.current = max(c.currents[0], c.currents[1], c.currents[2])
+}
+
+// updateTripCurve accounts for the overload of the most loaded phase
+func (c *Circuit) updateTripCurve() {
+	if c.tripCurve == nil {
+		return
+	}
+
+	var overload float64
+	for i, limit := range c.phaseLimits() {
+		if limit > 0 {
+			overload = max(overload, c.currents[i]/limit)
+		}
+	}
+
+	c.tripCurve.Update(c.clock.Now(), overload)
+	if overload > 1 {
+		c.log.DEBUG.Printf("overload: %.0f%%, trip curve load: %.0f%%", 100*overload, 100*c.tripCurve.Load())
 	}
 }
 
@@ -271,6 +389,7 @@ func (c *Circuit) updateMeters() error {
 			return err
 		}, modbus.Backoff()); err != nil {
 			c.overloadOnError(c.currentUpdated, &c.current)
+			c.currents = [3]float64{c.current, c.current, c.current}
 			return fmt.Errorf("circuit currents: %w", err)
 		}
 
@@ -282,7 +401,8 @@ func (c *Circuit) updateMeters() error {
 			}
 		}
 
-		c.current = max(util.SignFromPower(i1, p1), util.SignFromPower(i2, p2), util.SignFromPower(i3, p3))
+		c.currents = [3]float64{util.SignFromPower(i1, p1), util.SignFromPower(i2, p2), util.SignFromPower(i3, p3)}
+		c.current = max(c.currents[0], c.currents[1], c.currents[2])
 		c.currentUpdated = time.Now()
 	}
 
@@ -291,7 +411,13 @@ func (c *Circuit) updateMeters() error {
 
 func (c *Circuit) Update(loadpoints []api.CircuitLoad) (err error) {
 	maxPower := c.GetMaxPower()
-	maxCurrent := c.GetMaxCurrent()
+	limits := c.phaseLimits()
+
+	// grants are reflected by the new measurements
+	c.mu.Lock()
+	c.reservedPower = 0
+	c.reservedCurrents = [3]float64{}
+	c.mu.Unlock()
 
 	defer func() {
 		if maxPower != 0 && c.power > maxPower {
@@ -300,10 +426,15 @@ func (c *Circuit) Update(loadpoints []api.CircuitLoad) (err error) {
 			c.log.DEBUG.Printf("power: %.0fW", c.power)
 		}
 
-		if maxCurrent != 0 && c.current > maxCurrent {
-			c.log.WARN.Printf("over current detected: %.3gA > %.3gA", c.current, maxCurrent)
-		} else {
-			c.log.DEBUG.Printf("current: %.3gA", c.current)
+		var over bool
+		for i, limit := range limits {
+			if limit != 0 && c.currents[i] > limit {
+				c.log.WARN.Printf("over current detected: L%d %.3gA > %.3gA", i+1, c.currents[i], limit)
+				over = true
+			}
+		}
+		if !over {
+			c.log.DEBUG.Printf("current: %.3gA", c.currents)
 		}
 	}()
 
@@ -314,16 +445,35 @@ func (c *Circuit) Update(loadpoints []api.CircuitLoad) (err error) {
 		}
 	}
 
+	defer c.updateTripCurve()
+
 	// meter available
 	if c.meter != nil {
-		return c.updateMeters()
+		if err := c.updateMeters(); err != nil {
+			return err
+		}
+	} else {
+		c.updateLoadpoints(loadpoints)
+		for _, ch := range c.children {
+			c.power += ch.GetChargePower()
+			c.addCurrents(ch.GetPhaseCurrents())
+		}
 	}
 
-	// no meter available
-	c.updateLoadpoints(loadpoints)
-	for _, ch := range c.children {
-		c.power += ch.GetChargePower()
-		c.current += ch.GetMaxPhaseCurrent()
+	if len(c.loads) == 0 {
+		return nil
+	}
+
+	c.loadsMu.Lock()
+	defer c.loadsMu.Unlock()
+
+	// sheddable loads are not considered critical
+	if err := c.updateLoads(); err != nil {
+		c.log.ERROR.Printf("load: %v", err)
+	}
+
+	if err := c.shedLoads(); err != nil {
+		c.log.ERROR.Printf("load: %v", err)
 	}
 
 	return nil
@@ -339,48 +489,121 @@ func (c *Circuit) GetMaxPhaseCurrent() float64 {
 	return c.current
 }
 
-// ValidatePower validates power request
-func (c *Circuit) ValidatePower(old, new float64) float64 {
+// GetPhaseCurrents returns the actual current per phase
+func (c *Circuit) GetPhaseCurrents() [3]float64 {
+	return c.currents
+}
+
+// GetRemainingPower returns the power that can still be granted until the next update, limited by parent circuits
+func (c *Circuit) GetRemainingPower() float64 {
+	res := math.MaxFloat64
+
 	if maxPower := c.GetMaxPower(); maxPower != 0 {
+		c.mu.RLock()
+		res = maxPower - c.power - c.reservedPower
+		c.mu.RUnlock()
+	}
+
+	if c.parent == nil {
+		return res
+	}
+
+	return min(res, c.parent.GetRemainingPower())
+}
+
+// ValidatePower validates power request.
+// Granted increases are reserved until the next update so that concurrent requests cannot exceed the limit.
+func (c *Circuit) ValidatePower(old, new float64) float64 {
+	maxPower := c.GetMaxPower()
+
+	c.mu.Lock()
+	if maxPower != 0 {
 		delta := max(0, new-old)
-		potential := maxPower - c.power
+		potential := maxPower - c.power - c.reservedPower
 
 		if delta > potential {
 			capped := min(new, max(0, old+potential))
-			c.log.DEBUG.Printf("validate power: %.0fW + (%.0fW -> %.0fW) > %.0fW capped at %.0fW", c.power, old, new, maxPower, capped)
+			c.log.DEBUG.Printf("validate power: %.0fW + %.0fW reserved + (%.0fW -> %.0fW) > %.0fW capped at %.0fW", c.power, c.reservedPower, old, new, maxPower, capped)
 			new = capped
 		} else {
-			c.log.TRACE.Printf("validate power: %.0fW + (%.0fW -> %.0fW) <= %.0fW ok", c.power, old, new, maxPower)
+			c.log.TRACE.Printf("validate power: %.0fW + %.0fW reserved + (%.0fW -> %.0fW) <= %.0fW ok", c.power, c.reservedPower, old, new, maxPower)
 		}
 	}
+	c.reservedPower += max(0, new-old)
+	c.mu.Unlock()
 
 	if c.parent == nil {
 		return new
 	}
 
-	return c.parent.ValidatePower(old, new)
+	res := c.parent.ValidatePower(old, new)
+
+	// release the reservation exceeding the parent's grant
+	c.mu.Lock()
+	c.reservedPower -= max(0, new-old) - max(0, res-old)
+	c.mu.Unlock()
+
+	return res
 }
 
-// ValidateCurrent validates current request
+// ValidateCurrent validates current request on all phases
 func (c *Circuit) ValidateCurrent(old, new float64) float64 {
-	if maxCurrent := c.GetMaxCurrent(); maxCurrent != 0 {
+	return c.ValidatePhaseCurrent([3]bool{true, true, true}, old, new)
+}
+
+// ValidatePhaseCurrent validates current request on the given phases.
+// Existing overload is tolerated within the trip curve, increases are limited to the max current.
+// Granted increases are reserved until the next update so that concurrent requests cannot exceed the limit.
+func (c *Circuit) ValidatePhaseCurrent(phases [3]bool, old, new float64) float64 {
+	allowance := 1.0
+	if c.tripCurve != nil && new <= old {
+		allowance = c.tripCurve.Allowance(tripMargin)
+	}
+
+	limits := c.phaseLimits()
+
+	c.mu.Lock()
+	for i, limit := range limits {
+		if limit == 0 || !phases[i] {
+			continue
+		}
+
+		limit *= allowance
 		delta := max(0, new-old)
-		potential := maxCurrent - c.current
+		potential := limit - c.currents[i] - c.reservedCurrents[i]
 
 		if delta > potential {
 			capped := min(new, max(0, old+potential))
-			c.log.DEBUG.Printf("validate current: %.3gA + (%.3gA -> %.3gA) > %.3gA capped at %.3gA", c.current, old, new, maxCurrent, capped)
+			c.log.DEBUG.Printf("validate current: L%d %.3gA + %.3gA reserved + (%.3gA -> %.3gA) > %.3gA capped at %.3gA", i+1, c.currents[i], c.reservedCurrents[i], old, new, limit, capped)
 			new = capped
 		} else {
-			c.log.TRACE.Printf("validate current: %.3gA + (%.3gA -> %.3gA) <= %.3gA ok", c.current, old, new, maxCurrent)
+			c.log.TRACE.Printf("validate current: L%d %.3gA + %.3gA reserved + (%.3gA -> %.3gA) <= %.3gA ok", i+1, c.currents[i], c.reservedCurrents[i], old, new, limit)
 		}
 	}
+	c.reserveCurrents(phases, max(0, new-old))
+	c.mu.Unlock()
 
 	if c.parent == nil {
 		return new
 	}
 
-	return c.parent.ValidateCurrent(old, new)
+	res := c.parent.ValidatePhaseCurrent(phases, old, new)
+
+	// release the reservation exceeding the parent's grant
+	c.mu.Lock()
+	c.reserveCurrents(phases, max(0, res-old)-max(0, new-old))
+	c.mu.Unlock()
+
+	return res
+}
+
+// reserveCurrents adds the granted current to the given phases
+func (c *Circuit) reserveCurrents(phases [3]bool, current float64) {
+	for i, used := range phases {
+		if used {
+			c.reservedCurrents[i] += current
+		}
+	}
 }
 
 func (c *Circuit) Dim(dim bool) {
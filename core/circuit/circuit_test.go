@@ -2,7 +2,9 @@ package circuit
 
 import (
 	"testing"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/stretchr/testify/assert"
@@ -149,3 +151,134 @@ func TestWrapCycleDetection(t *testing.T) {
 	require.NoError(t, lpc.setParent(pc))
 	require.Error(t, pc.Wrap(lpc))
 }
+
+func TestCircuitPhaseCurrents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	m := struct {
+		*api.MockMeter
+		*api.MockPhaseCurrents
+	}{
+		api.NewMockMeter(ctrl),
+		api.NewMockPhaseCurrents(ctrl),
+	}
+
+	c, err := New(util.NewLogger("foo"), "foo", 16, 0, m, 0)
+	require.NoError(t, err)
+	require.NoError(t, c.setMaxPhaseCurrents([]float64{0, 0, 10}))
+
+	m.MockMeter.EXPECT().CurrentPower().Return(0.0, nil).AnyTimes()
+	m.MockPhaseCurrents.EXPECT().Currents().Return(5.0, 5.0, 8.0, nil).AnyTimes()
+	require.NoError(t, c.Update(nil))
+
+	assert.Equal(t, [3]float64{5, 5, 8}, c.GetPhaseCurrents())
+	assert.Equal(t, 11.0, c.ValidatePhaseCurrent([3]bool{true, false, false}, 0, 16), "L1")
+	assert.Equal(t, 2.0, c.ValidatePhaseCurrent([3]bool{false, false, true}, 0, 16), "L3")
+
+	require.NoError(t, c.Update(nil))
+	assert.Equal(t, 2.0, c.ValidateCurrent(0, 16), "all phases")
+}
+
+func TestCircuitReservation(t *testing.T) {
+	log := util.NewLogger("foo")
+
+	pc, err := New(log, "parent", 20, 10000, nil, 0)
+	require.NoError(t, err)
+	c, err := New(log, "child", 0, 0, nil, 0)
+	require.NoError(t, err)
+	require.NoError(t, c.setParent(pc))
+
+	require.NoError(t, pc.Update(nil))
+
+	// granted increases are reserved until next update
+	assert.Equal(t, 16.0, c.ValidateCurrent(0, 16))
+	assert.Equal(t, 4.0, c.ValidateCurrent(0, 16))
+	assert.Equal(t, 0.0, c.ValidatePhaseCurrent([3]bool{true, false, false}, 0, 16))
+	assert.Equal(t, 6.0, c.ValidateCurrent(6, 8), "existing current kept")
+
+	assert.Equal(t, 6000.0, c.ValidatePower(0, 6000))
+	assert.Equal(t, 4000.0, pc.GetRemainingPower())
+	assert.Equal(t, 4000.0, c.ValidatePower(0, 6000))
+	assert.Equal(t, 0.0, c.GetRemainingPower())
+
+	// reset on update
+	require.NoError(t, pc.Update(nil))
+	assert.Equal(t, 16.0, c.ValidateCurrent(0, 16))
+	assert.Equal(t, 10000.0, c.GetRemainingPower())
+}
+
+func TestCircuitTripCurve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clock := clock.NewMock()
+
+	m := struct {
+		*api.MockMeter
+		*api.MockPhaseCurrents
+	}{
+		api.NewMockMeter(ctrl),
+		api.NewMockPhaseCurrents(ctrl),
+	}
+
+	c, err := New(util.NewLogger("foo"), "foo", 10, 0, m, 0)
+	require.NoError(t, err)
+
+	c.clock = clock
+	c.tripCurve, err = NewTripCurve([]TripPoint{{1.45, 5 * time.Minute}, {1.13, time.Hour}})
+	require.NoError(t, err)
+
+	m.MockMeter.EXPECT().CurrentPower().Return(0.0, nil).AnyTimes()
+	m.MockPhaseCurrents.EXPECT().Currents().Return(12.0, 12.0, 12.0, nil).AnyTimes()
+
+	update := func() {
+		require.NoError(t, c.Update(nil))
+		clock.Add(time.Minute)
+	}
+
+	// existing overload tolerated, increase capped
+	update()
+	assert.Equal(t, 6.0, c.ValidateCurrent(6, 6))
+	assert.Equal(t, 4.0, c.ValidateCurrent(6, 8))
+
+	for range 3 {
+		update()
+		assert.Equal(t, 6.0, c.ValidateCurrent(6, 6))
+	}
+
+	// trip budget exhausted after 5 minutes at 120%
+	update()
+	update()
+	assert.Equal(t, 1.0, c.tripCurve.Load())
+	assert.Equal(t, 4.0, c.ValidateCurrent(6, 6))
+}
+
+func TestTripCurve(t *testing.T) {
+	_, err := NewTripCurve([]TripPoint{{1.13, time.Minute}, {1.45, time.Hour}})
+	require.Error(t, err, "increasing duration")
+
+	tc, err := NewTripCurve([]TripPoint{{1.13, time.Hour}, {1.45, 5 * time.Minute}, {2, 10 * time.Second}})
+	require.NoError(t, err)
+
+	now := time.Now()
+	tc.Update(now, 1.3)
+	assert.Equal(t, 2.0, tc.Allowance(time.Second))
+
+	now = now.Add(time.Minute)
+	tc.Update(now, 1.3)
+	assert.InDelta(t, 0.2, tc.Load(), 1e-9)
+	assert.Equal(t, 1.45, tc.Allowance(time.Minute))
+
+	now = now.Add(4 * time.Minute)
+	tc.Update(now, 1.3)
+	assert.Equal(t, 1.0, tc.Load())
+	assert.Equal(t, 1.0, tc.Allowance(time.Minute))
+
+	// cooling down
+	now = now.Add(30 * time.Minute)
+	tc.Update(now, 0.5)
+	assert.InDelta(t, 0.5, tc.Load(), 1e-9)
+
+	// beyond curve trips immediately
+	now = now.Add(time.Second)
+	tc.Update(now, 3)
+	assert.Equal(t, 1.0, tc.Load())
+}
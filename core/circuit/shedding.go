@@ -0,0 +1,242 @@
+package circuit
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+const (
+	// DefaultHysteresis is the default power headroom required for restoring shed loads
+	DefaultHysteresis = 500
+
+	// DefaultRestoreDelay is the default minimum time a load remains shed
+	DefaultRestoreDelay = 5 * time.Minute
+)
+
+// Voltage is the operating voltage for converting load power to current, set from the site configuration
+var Voltage = 230.0
+
+// LoadConfig defines a sheddable consumer switched via a charger, e.g. switchsocket or homeassistant-switch
+type LoadConfig struct {
+	Charger  string  // charger reference
+	Priority int     // loads with lower priority are shed first and restored last
+	Power    float64 // nominal power if not measured
+	Phase    int     // phase the load is connected to, 0 for three-phase loads
+}
+
+// Load is a sheddable non-ev consumer
+type Load struct {
+	name     string
+	charger  api.Charger
+	priority int
+	nominal  float64
+	phase    int
+
+	power   float64   // measured or nominal power while enabled
+	shed    bool      // load has been shed
+	updated time.Time // last time the load was switched
+}
+
+// NewLoad creates a sheddable load from config
+func NewLoad(cc LoadConfig) (*Load, error) {
+	dev, err := config.Chargers().ByName(cc.Charger)
+	if err != nil {
+		return nil, err
+	}
+
+	if cc.Phase < 0 || cc.Phase > 3 {
+		return nil, fmt.Errorf("invalid phase: %d", cc.Phase)
+	}
+
+	charger := dev.Instance()
+	if charger == nil {
+		return nil, fmt.Errorf("missing charger instance: %s", cc.Charger)
+	}
+
+	return &Load{
+		name:     cc.Charger,
+		charger:  charger,
+		priority: cc.Priority,
+		nominal:  cc.Power,
+		phase:    cc.Phase,
+	}, nil
+}
+
+// Shed returns true if the load has been shed
+func (l *Load) Shed() bool {
+	return l.shed
+}
+
+// update reads the load's power, nominal power is assumed if enabled but not measured
+func (l *Load) update() error {
+	enabled, err := l.charger.Enabled()
+	if err != nil {
+		return err
+	}
+
+	l.power = 0
+
+	if m, ok := api.Cap[api.Meter](l.charger); ok {
+		if l.power, err = m.CurrentPower(); err != nil {
+			return err
+		}
+	} else if enabled {
+		l.power = l.nominal
+	}
+
+	return nil
+}
+
+// restorePower returns the power expected when restoring the load
+func (l *Load) restorePower() float64 {
+	return max(l.power, l.nominal)
+}
+
+// phaseCurrents returns the currents drawn by the given power, spread evenly if connected to all phases
+func (l *Load) phaseCurrents(power float64) [3]float64 {
+	var res [3]float64
+
+	if l.phase == 0 {
+		for i := range res {
+			res[i] = power / (3 * Voltage)
+		}
+	} else {
+		res[l.phase-1] = power / Voltage
+	}
+
+	return res
+}
+
+// setLoads sets the sheddable loads, sorted by shedding order
+func (c *Circuit) setLoads(loads []*Load, hysteresis float64, restoreDelay time.Duration) error {
+	if c.GetMaxPower() == 0 && c.GetMaxCurrent() == 0 {
+		return errors.New("load shedding requires max power or max current")
+	}
+
+	slices.SortStableFunc(loads, func(a, b *Load) int {
+		return cmp.Compare(a.priority, b.priority)
+	})
+
+	c.loads = loads
+	c.hysteresis = hysteresis
+	c.restoreDelay = restoreDelay
+
+	return nil
+}
+
+// updateLoads measures the sheddable loads and adds their power and currents if the circuit has no meter
+func (c *Circuit) updateLoads() error {
+	var errs error
+
+	for _, l := range c.loads {
+		// keep last power of shed loads for restoring
+		if l.shed {
+			continue
+		}
+
+		if err := l.update(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%s: %w", l.name, err))
+			continue
+		}
+
+		if c.meter == nil {
+			c.power += l.power
+			c.addCurrents(l.phaseCurrents(l.power))
+		}
+	}
+
+	return errs
+}
+
+// overloaded returns true if power or any phase current exceed the limits by the given load
+func (c *Circuit) overloaded(power float64, currents [3]float64) bool {
+	if maxPower := c.GetMaxPower(); maxPower > 0 && c.power+power > maxPower {
+		return true
+	}
+
+	for i, limit := range c.phaseLimits() {
+		if limit > 0 && c.currents[i]+currents[i] > limit {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shedLoads sheds one load per update in priority order while overloaded or dimmed.
+// Shed loads are restored in reverse order once the headroom exceeds their power plus hysteresis.
+func (c *Circuit) shedLoads() error {
+	dimmed := c.Dimmed()
+	now := c.clock.Now()
+
+	if dimmed || c.overloaded(0, [3]float64{}) {
+		// loads switched off otherwise are left untouched
+		idx := slices.IndexFunc(c.loads, func(l *Load) bool { return !l.shed && l.power > 0 })
+		if idx < 0 {
+			return nil
+		}
+
+		l := c.loads[idx]
+		if err := l.charger.Enable(false); err != nil {
+			return fmt.Errorf("%s shed: %w", l.name, err)
+		}
+
+		c.log.INFO.Printf("shed load: %s (%.0fW)", l.name, l.power)
+		l.shed, l.updated = true, now
+
+		if c.meter == nil {
+			c.power -= l.power
+			c.addCurrents(l.phaseCurrents(-l.power))
+		}
+
+		return nil
+	}
+
+	// restore in reverse order
+	for _, l := range slices.Backward(c.loads) {
+		if !l.shed {
+			continue
+		}
+
+		if power := l.restorePower() + c.hysteresis; now.Sub(l.updated) < c.restoreDelay || c.overloaded(power, l.phaseCurrents(power)) {
+			return nil
+		}
+
+		if err := l.charger.Enable(true); err != nil {
+			return fmt.Errorf("%s restore: %w", l.name, err)
+		}
+
+		c.log.INFO.Printf("restore load: %s", l.name)
+		l.shed, l.updated = false, now
+
+		return nil
+	}
+
+	return nil
+}
+
+// restoreLoads re-enables all shed loads
+func (c *Circuit) restoreLoads() {
+	c.loadsMu.Lock()
+	defer c.loadsMu.Unlock()
+
+	for _, l := range c.loads {
+		if !l.shed {
+			continue
+		}
+
+		if err := l.charger.Enable(true); err != nil {
+			c.log.ERROR.Printf("load: %s restore: %v", l.name, err)
+			continue
+		}
+
+		c.log.INFO.Printf("restore load: %s", l.name)
+		l.shed, l.updated = false, c.clock.Now()
+	}
+}
@@ -0,0 +1,149 @@
+package circuit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type switchLoad struct {
+	*api.MockCharger
+	enabled bool
+}
+
+func (l *switchLoad) Enabled() (bool, error) {
+	return l.enabled, nil
+}
+
+func (l *switchLoad) Enable(enable bool) error {
+	l.enabled = enable
+	return nil
+}
+
+func TestLoadShedding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	clock := clock.NewMock()
+
+	c, err := New(util.NewLogger("foo"), "", 0, 6500, nil, 0)
+	require.NoError(t, err)
+	c.clock = clock
+
+	pool := &switchLoad{MockCharger: api.NewMockCharger(ctrl), enabled: true}
+	sauna := &switchLoad{MockCharger: api.NewMockCharger(ctrl), enabled: true}
+
+	require.NoError(t, c.setLoads([]*Load{
+		{name: "sauna", charger: sauna, priority: 2, nominal: 6000},
+		{name: "pool", charger: pool, priority: 1, nominal: 1000},
+	}, DefaultHysteresis, DefaultRestoreDelay))
+
+	// 7kW > 6.5kW, shed lowest priority first
+	require.NoError(t, c.Update(nil))
+	assert.False(t, pool.enabled)
+	assert.True(t, sauna.enabled)
+	assert.Equal(t, 6000.0, c.GetChargePower())
+
+	// restore delay not elapsed
+	c.SetMaxPower(10000)
+	clock.Add(time.Minute)
+	require.NoError(t, c.Update(nil))
+	assert.False(t, pool.enabled)
+
+	// restore requires hysteresis
+	clock.Add(DefaultRestoreDelay)
+	c.SetMaxPower(7200)
+	require.NoError(t, c.Update(nil))
+	assert.False(t, pool.enabled)
+
+	c.SetMaxPower(7500)
+	require.NoError(t, c.Update(nil))
+	assert.True(t, pool.enabled)
+
+	// dimming sheds all loads, one per update
+	c.Dim(true)
+	require.NoError(t, c.Update(nil))
+	require.NoError(t, c.Update(nil))
+	assert.False(t, pool.enabled)
+	assert.False(t, sauna.enabled)
+
+	// restored after dimming in reverse order
+	c.Dim(false)
+	clock.Add(DefaultRestoreDelay)
+	c.SetMaxPower(20000)
+	require.NoError(t, c.Update(nil))
+	require.NoError(t, c.Update(nil))
+	assert.True(t, pool.enabled)
+	assert.True(t, sauna.enabled)
+}
+
+func TestLoadSheddingPhaseCurrents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	clock := clock.NewMock()
+
+	c, err := New(util.NewLogger("foo"), "", 16, 0, nil, 0)
+	require.NoError(t, err)
+	c.clock = clock
+
+	heater := &switchLoad{MockCharger: api.NewMockCharger(ctrl), enabled: true}
+	boiler := &switchLoad{MockCharger: api.NewMockCharger(ctrl), enabled: true}
+
+	require.NoError(t, c.setLoads([]*Load{
+		{name: "heater", charger: heater, priority: 1, nominal: 6900},
+		{name: "boiler", charger: boiler, priority: 2, nominal: 3000, phase: 2},
+	}, DefaultHysteresis, DefaultRestoreDelay))
+
+	// 10A per phase plus 13A on L2 > 16A, shed lowest priority first
+	require.NoError(t, c.Update(nil))
+	assert.False(t, heater.enabled)
+	assert.True(t, boiler.enabled)
+	assert.InDelta(t, 3000/Voltage, c.GetPhaseCurrents()[1], 1e-6)
+	assert.Equal(t, 0.0, c.GetPhaseCurrents()[0])
+
+	// restore requires headroom on all phases
+	clock.Add(DefaultRestoreDelay)
+	require.NoError(t, c.Update(nil))
+	assert.False(t, heater.enabled)
+
+	c.SetMaxCurrent(32)
+	require.NoError(t, c.Update(nil))
+	assert.True(t, heater.enabled)
+}
+
+func TestLoadSheddingRestart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	pool := &switchLoad{MockCharger: api.NewMockCharger(ctrl), enabled: true}
+
+	start := func() *Circuit {
+		c, err := New(util.NewLogger("foo"), "", 0, 500, nil, 0)
+		require.NoError(t, err)
+		c.clock = clock.NewMock()
+
+		require.NoError(t, c.setLoads([]*Load{
+			{name: "pool", charger: pool, priority: 1, nominal: 1000},
+		}, DefaultHysteresis, DefaultRestoreDelay))
+
+		return c
+	}
+
+	c := start()
+	require.NoError(t, c.Update(nil))
+	assert.False(t, pool.enabled)
+
+	// shed loads are restored on shutdown
+	c.restoreLoads()
+	assert.True(t, pool.enabled)
+
+	// and shed again after restart
+	c = start()
+	require.NoError(t, c.Update(nil))
+	assert.False(t, pool.enabled)
+	assert.True(t, c.loads[0].Shed())
+}
@@ -0,0 +1,98 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// TripPoint is a point of a fuse's time-current characteristic
+type TripPoint struct {
+	Overload float64       // current relative to max current
+	Duration time.Duration // duration the overload is tolerated before tripping
+}
+
+// TripCurve models the thermal load of a fuse based on its time-current characteristic.
+// Overload consumes the trip budget proportionally to the tolerated duration, current
+// below the limit restores it within the duration of the lowest overload.
+type TripCurve struct {
+	points  []TripPoint // sorted by ascending overload
+	load    float64     // consumed trip budget 0..1
+	updated time.Time
+}
+
+// NewTripCurve creates a trip curve from its points
+func NewTripCurve(points []TripPoint) (*TripCurve, error) {
+	if len(points) == 0 {
+		return nil, errors.New("missing trip curve points")
+	}
+
+	points = slices.Clone(points)
+	slices.SortFunc(points, func(a, b TripPoint) int {
+		if a.Overload < b.Overload {
+			return -1
+		}
+		if a.Overload > b.Overload {
+			return 1
+		}
+		return 0
+	})
+
+	for i, p := range points {
+		if p.Overload <= 1 || p.Duration <= 0 {
+			return nil, fmt.Errorf("invalid trip curve point: %.2f for %v", p.Overload, p.Duration)
+		}
+		if i > 0 && p.Duration >= points[i-1].Duration {
+			return nil, errors.New("trip curve durations must decrease with increasing overload")
+		}
+	}
+
+	return &TripCurve{points: points}, nil
+}
+
+// tripTime returns the duration an overload is tolerated, conservatively using the next higher point
+func (tc *TripCurve) tripTime(overload float64) time.Duration {
+	for _, p := range tc.points {
+		if overload <= p.Overload {
+			return p.Duration
+		}
+	}
+	return 0
+}
+
+// Update accounts for the overload since the last update
+func (tc *TripCurve) Update(now time.Time, overload float64) {
+	if !tc.updated.IsZero() {
+		dt := float64(now.Sub(tc.updated))
+
+		switch d := tc.tripTime(overload); {
+		case overload <= 1:
+			tc.load -= dt / float64(tc.points[0].Duration)
+		case d == 0:
+			tc.load = 1
+		default:
+			tc.load += dt / float64(d)
+		}
+
+		tc.load = min(max(tc.load, 0), 1)
+	}
+
+	tc.updated = now
+}
+
+// Load returns the consumed trip budget 0..1
+func (tc *TripCurve) Load() float64 {
+	return tc.load
+}
+
+// Allowance returns the overload that can still be tolerated for at least the given duration
+func (tc *TripCurve) Allowance(margin time.Duration) float64 {
+	res := 1.0
+	for _, p := range tc.points {
+		if time.Duration((1-tc.load)*float64(p.Duration)) >= margin {
+			res = max(res, p.Overload)
+		}
+	}
+	return res
+}
@@ -35,7 +35,7 @@ func (a *adapter) Release(v api.Vehicle) {
 	a.c.release(v)
 }
 
-func (a *adapter) IdentifyVehicleByStatus() api.Vehicle {
+func (a *adapter) IdentifyVehicleByStatus(position loadpoint.PositionConfig) api.Vehicle {
 	available := a.c.availableDetectibleVehicles(a.lp)
-	return a.c.identifyVehicleByStatus(available)
+	return a.c.identifyVehicleByStatus(available, position)
 }
@@ -19,6 +19,7 @@ type API interface {
 	// Release releases a vehicle from a loadpoint
 	Release(api.Vehicle)
 
-	// IdentifyVehicleByStatus returns an available vehicle that is currently connected or charging
-	IdentifyVehicleByStatus() api.Vehicle
+	// IdentifyVehicleByStatus returns an available vehicle that is currently connected or charging,
+	// preferring vehicles parked at the given position
+	IdentifyVehicleByStatus(position loadpoint.PositionConfig) api.Vehicle
 }
@@ -127,9 +127,11 @@ func (c *Coordinator) availableDetectibleVehicles(owner loadpoint.API) []api.Veh
 	return res
 }
 
-// identifyVehicleByStatus finds active vehicle by charge state
-func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle) api.Vehicle {
-	var res api.Vehicle
+// identifyVehicleByStatus finds active vehicle by charge state.
+// If the loadpoint position is configured, vehicles parked elsewhere are excluded
+// and vehicles parked at the loadpoint are preferred.
+func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle, position loadpoint.PositionConfig) api.Vehicle {
+	var res []api.Vehicle
 
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -148,15 +150,58 @@ func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle) api.Vehic
 
 			// vehicle is plugged or charging, so it should be the right one
 			if status == api.StatusB || status == api.StatusC {
-				if res != nil {
-					c.log.WARN.Println("vehicle status: >1 matches, giving up")
-					return nil
-				}
+				res = append(res, vehicle)
+			}
+		}
+	}
+
+	if position.Configured() {
+		res = c.filterByPosition(res, position)
+	}
+
+	switch len(res) {
+	case 0:
+		return nil
+	case 1:
+		return res[0]
+	default:
+		c.log.WARN.Println("vehicle status: >1 matches, giving up")
+		return nil
+	}
+}
+
+// filterByPosition excludes vehicles parked away from the position and returns
+// the vehicles parked at the position if any
+func (c *Coordinator) filterByPosition(vehicles []api.Vehicle, position loadpoint.PositionConfig) []api.Vehicle {
+	var near, unknown []api.Vehicle
 
-				res = vehicle
+	for _, vehicle := range vehicles {
+		vp, ok := api.Cap[api.VehiclePosition](vehicle)
+		if !ok {
+			unknown = append(unknown, vehicle)
+			continue
+		}
+
+		lat, lon, err := vp.Position()
+		if err != nil {
+			if !loadpoint.AcceptableError(err) {
+				c.log.ERROR.Println("vehicle position:", err)
 			}
+			unknown = append(unknown, vehicle)
+			continue
+		}
+
+		if d := position.Distance(lat, lon); d > position.Radius {
+			c.log.DEBUG.Printf("vehicle position: %s excluded, %.0fm away", vehicle.GetTitle(), d)
+		} else {
+			c.log.DEBUG.Printf("vehicle position: %s at loadpoint, %.0fm away", vehicle.GetTitle(), d)
+			near = append(near, vehicle)
 		}
 	}
 
-	return res
+	if len(near) > 0 {
+		return near
+	}
+
+	return unknown
 }
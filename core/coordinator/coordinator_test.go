@@ -52,7 +52,7 @@ func TestVehicleDetectByStatus(t *testing.T) {
 		v2.MockChargeState.EXPECT().Status().Return(tc.v2, nil)
 
 		available := c.availableDetectibleVehicles(lp) // include id-able vehicles
-		res := c.identifyVehicleByStatus(available)
+		res := c.identifyVehicleByStatus(available, loadpoint.PositionConfig{})
 		if tc.res != res {
 			t.Errorf("expected %v, got %v", tc.res, res)
 		}
@@ -64,3 +64,39 @@ func TestVehicleDetectByStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestVehicleDetectByPosition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	type vehicle struct {
+		*api.MockVehicle
+		*api.MockChargeState
+		*api.MockVehiclePosition
+	}
+
+	v1 := &vehicle{api.NewMockVehicle(ctrl), api.NewMockChargeState(ctrl), api.NewMockVehiclePosition(ctrl)}
+	v2 := &vehicle{api.NewMockVehicle(ctrl), api.NewMockChargeState(ctrl), api.NewMockVehiclePosition(ctrl)}
+
+	v1.MockVehicle.EXPECT().GetTitle().Return("v1").AnyTimes()
+	v2.MockVehicle.EXPECT().GetTitle().Return("v2").AnyTimes()
+	v1.MockChargeState.EXPECT().Status().Return(api.StatusB, nil).AnyTimes()
+	v2.MockChargeState.EXPECT().Status().Return(api.StatusB, nil).AnyTimes()
+
+	position := loadpoint.PositionConfig{Lat: 52.52, Lon: 13.405, Radius: 100}
+
+	// v1 at loadpoint, v2 ~1km away
+	v1.MockVehiclePosition.EXPECT().Position().Return(52.5201, 13.4051, nil).AnyTimes()
+	v2.MockVehiclePosition.EXPECT().Position().Return(52.53, 13.405, nil).AnyTimes()
+
+	c := New(util.NewLogger("foo"), []api.Vehicle{v1, v2})
+	available := c.availableDetectibleVehicles(nil)
+
+	// ambiguous without position
+	if res := c.identifyVehicleByStatus(available, loadpoint.PositionConfig{}); res != nil {
+		t.Errorf("expected nil, got %v", res)
+	}
+
+	if res := c.identifyVehicleByStatus(available, position); res != v1 {
+		t.Errorf("expected v1, got %v", res)
+	}
+}
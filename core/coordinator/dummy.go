@@ -24,6 +24,6 @@ func (a *dummy) Acquire(api.Vehicle) {}
 
 func (a *dummy) Release(api.Vehicle) {}
 
-func (a *dummy) IdentifyVehicleByStatus() api.Vehicle {
+func (a *dummy) IdentifyVehicleByStatus(loadpoint.PositionConfig) api.Vehicle {
 	return nil
 }
@@ -0,0 +1,150 @@
+package fingerprint
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// MinDuration is the charging duration required for a signature
+	MinDuration = 2 * time.Minute
+
+	// Window is the charging duration observed for a signature
+	Window = 10 * time.Minute
+
+	// rampThreshold is the share of max power defining the end of the power ramp
+	rampThreshold = 0.9
+
+	// limitMargin is the current offered above the drawn current for the vehicle to be considered unlimited
+	limitMargin = 2
+)
+
+// Signature is the charge behavior observed after enabling the charger
+type Signature struct {
+	Phases     int           `json:"phases"`     // active phases
+	MaxCurrent float64       `json:"maxCurrent"` // max accepted current per phase in A
+	MaxPower   float64       `json:"maxPower"`   // max onboard charger power in W
+	Ramp       time.Duration `json:"ramp"`       // duration until reaching max power
+}
+
+// Profile is a vehicle's signature learned from past sessions
+type Profile struct {
+	Signature
+	Sessions int `json:"sessions"`
+}
+
+// Learn updates the profile with the signature of another session
+func (p *Profile) Learn(s Signature) {
+	p.Sessions++
+	n := float64(p.Sessions)
+
+	// phases are not averaged, latest session wins
+	p.Phases = s.Phases
+	p.MaxCurrent += (s.MaxCurrent - p.MaxCurrent) / n
+	p.MaxPower += (s.MaxPower - p.MaxPower) / n
+	p.Ramp += time.Duration(float64(s.Ramp-p.Ramp) / n)
+}
+
+// likelihood returns how well the signature matches the profile (0..1)
+func (p Profile) likelihood(s Signature) float64 {
+	gauss := func(delta, sigma float64) float64 {
+		return math.Exp(-delta * delta / (2 * sigma * sigma))
+	}
+
+	res := gauss(s.MaxCurrent-p.MaxCurrent, 2) *
+		gauss(s.MaxPower-p.MaxPower, 0.1*p.MaxPower+300) *
+		gauss((s.Ramp-p.Ramp).Seconds(), 30)
+
+	if s.Phases != p.Phases {
+		res *= 0.05
+	}
+
+	return res
+}
+
+// Match returns the name of the best matching profile and the match confidence (0..1).
+// Confidence is reduced if other profiles match similarly well.
+func Match(s Signature, profiles map[string]Profile) (string, float64) {
+	var (
+		best      string
+		bestScore float64
+		sum       float64
+	)
+
+	for name, p := range profiles {
+		if p.Sessions == 0 {
+			continue
+		}
+
+		score := p.likelihood(s)
+		sum += score
+
+		if score > bestScore || score == bestScore && name < best {
+			best, bestScore = name, score
+		}
+	}
+
+	if sum == 0 {
+		return "", 0
+	}
+
+	return best, bestScore * bestScore / sum
+}
+
+type sample struct {
+	time  time.Time
+	power float64
+}
+
+// Observer records the charge behavior after enabling the charger
+type Observer struct {
+	start   time.Time
+	samples []sample
+	sig     Signature
+}
+
+// Reset clears the observation
+func (o *Observer) Reset() {
+	*o = Observer{}
+}
+
+// Update adds a measurement while charging. Max current and power are only sampled while the
+// offered current clearly exceeds the drawn current, i.e. the vehicle is not limited by the charger,
+// or while the charger offers its max current, i.e. the vehicle draws at least the sampled current.
+func (o *Observer) Update(now time.Time, power, current, offered, maxCurrent float64, phases int) {
+	if o.start.IsZero() {
+		o.start = now
+	}
+
+	if now.Sub(o.start) > Window {
+		return
+	}
+
+	o.samples = append(o.samples, sample{now, power})
+
+	o.sig.Phases = max(o.sig.Phases, phases)
+
+	if offered >= current+limitMargin || maxCurrent > 0 && offered >= maxCurrent {
+		o.sig.MaxCurrent = max(o.sig.MaxCurrent, current)
+		o.sig.MaxPower = max(o.sig.MaxPower, power)
+	}
+}
+
+// Signature returns the observed signature once charging for at least MinDuration.
+// Sessions where the vehicle was limited by the charger throughout don't have a signature.
+func (o *Observer) Signature() (Signature, bool) {
+	if len(o.samples) == 0 || o.samples[len(o.samples)-1].time.Sub(o.start) < MinDuration || o.sig.MaxPower <= 0 {
+		return Signature{}, false
+	}
+
+	res := o.sig
+
+	for _, s := range o.samples {
+		if s.power >= rampThreshold*o.sig.MaxPower {
+			res.Ramp = s.time.Sub(o.start)
+			break
+		}
+	}
+
+	return res, true
+}
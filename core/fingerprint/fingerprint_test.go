@@ -0,0 +1,73 @@
+package fingerprint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver(t *testing.T) {
+	var o Observer
+
+	now := time.Now()
+	for i, p := range []float64{1000, 5000, 10500, 11000, 11000} {
+		o.Update(now.Add(time.Duration(i)*30*time.Second), p, p/3/230, 32, 32, 3)
+	}
+
+	s, ok := o.Signature()
+	require.True(t, ok)
+	assert.Equal(t, 3, s.Phases)
+	assert.Equal(t, 11000.0, s.MaxPower)
+	assert.Equal(t, time.Minute, s.Ramp)
+
+	o.Reset()
+	_, ok = o.Signature()
+	assert.False(t, ok)
+
+	// limited by the charger, e.g. pv surplus
+	for i := range 5 {
+		o.Update(now.Add(time.Duration(i)*30*time.Second), 4140, 6, 6, 16, 3)
+	}
+
+	_, ok = o.Signature()
+	assert.False(t, ok)
+
+	o.Reset()
+
+	// drawing the charger's max current, e.g. 11kW vehicle at 16A charger
+	for i, p := range []float64{2000, 11000, 11000, 11000, 11000} {
+		o.Update(now.Add(time.Duration(i)*30*time.Second), p, p/3/230, 16, 16, 3)
+	}
+
+	s, ok = o.Signature()
+	require.True(t, ok)
+	assert.Equal(t, 11000.0, s.MaxPower)
+	assert.InDelta(t, 16.0, s.MaxCurrent, 0.1)
+	assert.Equal(t, 30*time.Second, s.Ramp)
+}
+
+func TestMatch(t *testing.T) {
+	var zoe, tesla Profile
+
+	zoe.Learn(Signature{Phases: 3, MaxCurrent: 32, MaxPower: 22000, Ramp: 30 * time.Second})
+	zoe.Learn(Signature{Phases: 3, MaxCurrent: 32, MaxPower: 21000, Ramp: 30 * time.Second})
+	assert.Equal(t, 21500.0, zoe.MaxPower)
+
+	tesla.Learn(Signature{Phases: 3, MaxCurrent: 16, MaxPower: 11000, Ramp: 90 * time.Second})
+
+	profiles := map[string]Profile{
+		"zoe":   zoe,
+		"tesla": tesla,
+		"new":   {},
+	}
+
+	name, confidence := Match(Signature{Phases: 3, MaxCurrent: 16, MaxPower: 10800, Ramp: time.Minute}, profiles)
+	assert.Equal(t, "tesla", name)
+	assert.Greater(t, confidence, 0.5)
+
+	// single phase charging matches neither
+	_, confidence = Match(Signature{Phases: 1, MaxCurrent: 16, MaxPower: 3600, Ramp: time.Minute}, profiles)
+	assert.Less(t, confidence, 0.1)
+}
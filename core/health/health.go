@@ -0,0 +1,141 @@
+package health
+
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+const (
+	// DefaultThreshold is the number of consecutive faulty cycles until the charger is degraded
+	DefaultThreshold = 3
+
+	// DefaultTimeout is the charging duration without power until the charger is stalled
+	// and the delay between recovery actions
+	DefaultTimeout = 5 * time.Minute
+
+	// minPower is the power below which a charging vehicle is considered not drawing power
+	minPower = 100 // W
+)
+
+//go:generate go tool enumer -type State -trimprefix State -transform=lower -text
+type State int
+
+// Health states
+const (
+	StateOk State = iota
+	StateDegraded
+	StateFailed
+)
+
+// Status is the charger's health status
+type Status struct {
+	State      State  `json:"state"`
+	Errors     int    `json:"errors"`     // communication errors
+	Stalls     int    `json:"stalls"`     // charging without drawing power
+	Faults     int    `json:"faults"`     // consecutive faulty cycles
+	Recoveries int    `json:"recoveries"` // executed recovery actions
+	LastError  string `json:"lastError,omitempty"`
+}
+
+// Monitor tracks charger reliability over control cycles and schedules recovery actions
+type Monitor struct {
+	clock     clock.Clock
+	threshold int
+	timeout   time.Duration
+	actions   int // number of available recovery actions
+
+	status    Status
+	next      int       // next recovery action
+	stalled   time.Time // charging without power since
+	recovered time.Time // last recovery action
+}
+
+// NewMonitor creates a health monitor for the given number of recovery actions
+func NewMonitor(clock clock.Clock, threshold int, timeout time.Duration, actions int) *Monitor {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Monitor{
+		clock:     clock,
+		threshold: threshold,
+		timeout:   timeout,
+		actions:   actions,
+	}
+}
+
+// Status returns the current health status
+func (m *Monitor) Status() Status {
+	return m.status
+}
+
+// Error records a failed control cycle
+func (m *Monitor) Error(err error) {
+	m.status.Errors++
+	m.status.LastError = err.Error()
+	m.stalled = time.Time{}
+	m.evaluate(true)
+}
+
+// Charging records a successful control cycle with the vehicle's charging state and power
+func (m *Monitor) Charging(charging bool, power float64) {
+	if !charging || power >= minPower {
+		m.stalled = time.Time{}
+		m.evaluate(false)
+		return
+	}
+
+	if m.stalled.IsZero() {
+		m.stalled = m.clock.Now()
+	}
+
+	if m.clock.Since(m.stalled) < m.timeout {
+		return
+	}
+
+	// count each stall once
+	if m.status.Faults == 0 {
+		m.status.Stalls++
+	}
+
+	m.evaluate(true)
+}
+
+func (m *Monitor) evaluate(fault bool) {
+	if !fault {
+		m.status.Faults = 0
+		m.status.State = StateOk
+		m.next = 0
+		return
+	}
+
+	m.status.Faults++
+
+	switch {
+	case m.status.State == StateOk && m.status.Faults >= m.threshold:
+		m.status.State = StateDegraded
+
+	// all recovery actions have been given time to take effect
+	case m.status.State == StateDegraded && m.next >= m.actions && m.clock.Since(m.recovered) >= m.timeout:
+		m.status.State = StateFailed
+	}
+}
+
+// Recovery returns the index of the next recovery action if due.
+// Recovery actions are executed in order, allowing each to take effect before the next one.
+func (m *Monitor) Recovery() (int, bool) {
+	if m.status.State != StateDegraded || m.next >= m.actions || m.clock.Since(m.recovered) < m.timeout {
+		return 0, false
+	}
+
+	action := m.next
+	m.next++
+	m.recovered = m.clock.Now()
+	m.status.Recoveries++
+
+	return action, true
+}
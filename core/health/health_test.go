@@ -0,0 +1,76 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	clock := clock.NewMock()
+	m := NewMonitor(clock, 3, time.Minute, 2)
+
+	for range 2 {
+		m.Error(errors.New("timeout"))
+	}
+	assert.Equal(t, StateOk, m.Status().State)
+
+	m.Error(errors.New("timeout"))
+	assert.Equal(t, Status{State: StateDegraded, Errors: 3, Faults: 3, LastError: "timeout"}, m.Status())
+
+	// first action immediately, second after timeout
+	action, ok := m.Recovery()
+	assert.True(t, ok)
+	assert.Equal(t, 0, action)
+
+	_, ok = m.Recovery()
+	assert.False(t, ok)
+
+	clock.Add(time.Minute)
+	action, ok = m.Recovery()
+	assert.True(t, ok)
+	assert.Equal(t, 1, action)
+
+	// all actions exhausted
+	m.Error(errors.New("timeout"))
+	assert.Equal(t, StateDegraded, m.Status().State)
+
+	clock.Add(time.Minute)
+	m.Error(errors.New("timeout"))
+	assert.Equal(t, StateFailed, m.Status().State)
+
+	_, ok = m.Recovery()
+	assert.False(t, ok)
+
+	// recovered
+	m.Charging(false, 0)
+	assert.Equal(t, Status{State: StateOk, Errors: 5, Recoveries: 2, LastError: "timeout"}, m.Status())
+}
+
+func TestStalls(t *testing.T) {
+	clock := clock.NewMock()
+	m := NewMonitor(clock, 2, time.Minute, 0)
+
+	m.Charging(true, 0)
+	clock.Add(30 * time.Second)
+	m.Charging(true, 0)
+	assert.Equal(t, Status{}, m.Status())
+
+	clock.Add(30 * time.Second)
+	m.Charging(true, 0)
+	assert.Equal(t, Status{State: StateOk, Stalls: 1, Faults: 1}, m.Status())
+
+	m.Charging(true, 0)
+	assert.Equal(t, Status{State: StateDegraded, Stalls: 1, Faults: 2}, m.Status())
+
+	// no recovery actions configured
+	_, ok := m.Recovery()
+	assert.False(t, ok)
+
+	// drawing power
+	m.Charging(true, 1e3)
+	assert.Equal(t, Status{State: StateOk, Stalls: 1}, m.Status())
+}
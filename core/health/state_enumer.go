@@ -0,0 +1,94 @@
+// Code generated by "enumer -type State -trimprefix State -transform=lower -text"; DO NOT EDIT.
+
+package health
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _StateName = "okdegradedfailed"
+
+var _StateIndex = [...]uint8{0, 2, 10, 16}
+
+const _StateLowerName = "okdegradedfailed"
+
+func (i State) String() string {
+	if i < 0 || i >= State(len(_StateIndex)-1) {
+		return fmt.Sprintf("State(%d)", i)
+	}
+	return _StateName[_StateIndex[i]:_StateIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _StateNoOp() {
+	var x [1]struct{}
+	_ = x[StateOk-(0)]
+	_ = x[StateDegraded-(1)]
+	_ = x[StateFailed-(2)]
+}
+
+var _StateValues = []State{StateOk, StateDegraded, StateFailed}
+
+var _StateNameToValueMap = map[string]State{
+	_StateName[0:2]:        StateOk,
+	_StateLowerName[0:2]:   StateOk,
+	_StateName[2:10]:       StateDegraded,
+	_StateLowerName[2:10]:  StateDegraded,
+	_StateName[10:16]:      StateFailed,
+	_StateLowerName[10:16]: StateFailed,
+}
+
+var _StateNames = []string{
+	_StateName[0:2],
+	_StateName[2:10],
+	_StateName[10:16],
+}
+
+// StateString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func StateString(s string) (State, error) {
+	if val, ok := _StateNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _StateNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to State values", s)
+}
+
+// StateValues returns all values of the enum
+func StateValues() []State {
+	return _StateValues
+}
+
+// StateStrings returns a slice of all String values of the enum
+func StateStrings() []string {
+	strs := make([]string, len(_StateNames))
+	copy(strs, _StateNames)
+	return strs
+}
+
+// IsAState returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i State) IsAState() bool {
+	for _, v := range _StateValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for State
+func (i State) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for State
+func (i *State) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = StateString(string(text))
+	return err
+}
@@ -2,28 +2,29 @@ package keys
 
 const (
 	// loadpoint settings
-	Title             = "title"            // loadpoint title
-	Mode              = "mode"             // charge mode
-	DefaultMode       = "defaultMode"      // default charge mode
-	Charger           = "charger"          // charger ref
-	Meter             = "meter"            // meter ref
-	Circuit           = "circuit"          // circuit ref
-	DefaultVehicle    = "vehicle"          // default vehicle ref
-	Priority          = "priority"         // priority
-	MinCurrent        = "minCurrent"       // min current
-	MaxCurrent        = "maxCurrent"       // max current
-	MinSoc            = "minSoc"           // min soc
-	MinSocNotReached  = "minSocNotReached" // min soc not reached
-	LimitSoc          = "limitSoc"         // limit soc
-	LimitEnergy       = "limitEnergy"      // limit energy
-	Soc               = "soc"
-	Thresholds        = "thresholds"
-	EnableThreshold   = "enableThreshold"
-	DisableThreshold  = "disableThreshold"
-	EnableDelay       = "enableDelay"
-	DisableDelay      = "disableDelay"
-	BatteryBoost      = "batteryBoost"
-	BatteryBoostLimit = "batteryBoostLimit"
+	Title              = "title"            // loadpoint title
+	Mode               = "mode"             // charge mode
+	DefaultMode        = "defaultMode"      // default charge mode
+	Charger            = "charger"          // charger ref
+	Meter              = "meter"            // meter ref
+	Circuit            = "circuit"          // circuit ref
+	DefaultVehicle     = "vehicle"          // default vehicle ref
+	Priority           = "priority"         // priority
+	MinCurrent         = "minCurrent"       // min current
+	MaxCurrent         = "maxCurrent"       // max current
+	MinSoc             = "minSoc"           // min soc
+	MinSocNotReached   = "minSocNotReached" // min soc not reached
+	LimitSoc           = "limitSoc"         // limit soc
+	LimitEnergy        = "limitEnergy"      // limit energy
+	Soc                = "soc"
+	Thresholds         = "thresholds"
+	EnableThreshold    = "enableThreshold"
+	DisableThreshold   = "disableThreshold"
+	EnableDelay        = "enableDelay"
+	DisableDelay       = "disableDelay"
+	AdaptiveThresholds = "adaptiveThresholds" // thresholds adapted to volatility
+	BatteryBoost       = "batteryBoost"
+	BatteryBoostLimit  = "batteryBoostLimit"
 
 	PhasesConfigured = "phasesConfigured" // desired phase mode (0/1/3, 0 = automatic), user selection
 	PhasesActive     = "phasesActive"     // expectedly active phases, taking vehicle into account (1/2/3)
@@ -33,6 +34,7 @@ const (
 	ChargerSinglePhase  = "chargerSinglePhase"  // api.PhaseDescriber: charger physical phases, sockets only
 	ChargerPhases1p3p   = "chargerPhases1p3p"   // api.PhaseSwitcher: 1p3p chargers
 	ChargerStatusReason = "chargerStatusReason" // either awaiting authorization or disconnect required
+	ChargerHealth       = "chargerHealth"       // charger health status
 
 	// loadpoint status
 	Enabled   = "enabled"   // loadpoint enabled
@@ -65,6 +67,7 @@ const (
 
 	// measurements
 	ChargePower       = "chargePower"       // charge power
+	DischargePower    = "dischargePower"    // vehicle discharge power
 	ChargeCurrents    = "chargeCurrents"    // charge currents
 	ChargeVoltages    = "chargeVoltages"    // charge voltages
 	ChargedEnergy     = "chargedEnergy"     // charged energy
@@ -90,6 +93,28 @@ const (
 	// repeating plans
 	RepeatingPlans = "repeatingPlans" // key to access all repeating plans in db
 
+	// departure guarantee
+	DepartureGuarantee       = "departureGuarantee"       // key to access vehicle departure guarantee in db
+	DepartureGuaranteeActive = "departureGuaranteeActive" // charging required for departure guarantee
+	DepartureGuaranteeSoc    = "departureGuaranteeSoc"    // soc required by departure guarantee
+
+	// thermal storage
+	ThermalPlan     = "thermalPlan"     // heating plan time slots with predicted temperatures
+	ThermalRequired = "thermalRequired" // heating required for keeping the lower comfort bound
+	OutsideTemp     = "outsideTemp"     // outside temperature
+
+	// sg-ready state scheduling
+	SgReadyState   = "sgReadyState"   // sg-ready operating state 1-4
+	SgReadyBoosts  = "sgReadyBoosts"  // sg-ready boost periods today
+	SgReadyHistory = "sgReadyHistory" // sg-ready state changes
+
+	// scheduled changes
+	Schedule     = "schedule"     // scheduled setting changes
+	ScheduleNext = "scheduleNext" // next scheduled setting change
+
+	// vehicle identification
+	Fingerprint = "fingerprint" // key to access learned vehicle charge behavior in db
+
 	// remote control
 	RemoteDisabled       = "remoteDisabled"       // remote disabled
 	RemoteDisabledSource = "remoteDisabledSource" // remote disabled source
@@ -105,4 +130,5 @@ const (
 	VehicleLimitSoc        = "vehicleLimitSoc"        // vehicle api soc limit
 	VehicleClimaterActive  = "vehicleClimaterActive"  // vehicle climater active
 	VehicleWelcomeActive   = "vehicleWelcomeActive"   // vehicle might need welcome charge
+	VehicleMatchConfidence = "vehicleMatchConfidence" // vehicle identified by charge behavior confidence
 )
@@ -11,10 +11,13 @@ const (
 	GridConfigured        = "gridConfigured"
 	Grid                  = "grid"
 	HomePower             = "homePower"
+	OffGrid               = "offGrid"
+	PhaseImbalance        = "phaseImbalance"
 	PrioritySoc           = "prioritySoc"
 	Pv                    = "pv"
 	PvEnergy              = "pvEnergy"
 	PvPower               = "pvPower"
+	PvLimit               = "pvLimit"
 	ResidualPower         = "residualPower"
 	SiteTitle             = "siteTitle"
 	SmartCostType         = "smartCostType"
@@ -47,8 +50,14 @@ const (
 	BufferStartSoc          = "bufferStartSoc"
 
 	// battery status
-	Battery     = "battery"
-	BatteryMode = "batteryMode"
+	Battery      = "battery"
+	BatteryMode  = "batteryMode"
+	BatteryStats = "batteryStats"
+
+	// battery power control
+	BatterySetpoint = "batterySetpoint"
+	Arbitrage       = "arbitrage"
+	ArbitrageProfit = "arbitrageProfit"
 
 	// external battery control
 	BatteryModeExternal = "batteryModeExternal"
@@ -14,14 +14,20 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/adaptive"
 	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/core/fingerprint"
+	"github.com/evcc-io/evcc/core/health"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/schedule"
 	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/core/sgready"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/core/thermal"
 	"github.com/evcc-io/evcc/core/vehicle"
 	"github.com/evcc-io/evcc/core/wrapper"
 	"github.com/evcc-io/evcc/messenger"
@@ -41,6 +47,7 @@ const (
 	evVehicleSoc          = "soc"        // vehicle soc progress
 	evVehicleUnidentified = "guest"      // vehicle unidentified
 	evVehicleAsleep       = "asleep"     // vehicle doesn't charge
+	evChargerHealth       = "health"     // charger health changed
 
 	pvTimer   = "pv"
 	pvEnable  = "enable"
@@ -93,14 +100,23 @@ type Loadpoint struct {
 	ChargerRef string `mapstructure:"charger"` // Charger reference
 	VehicleRef string `mapstructure:"vehicle"` // Vehicle reference
 	MeterRef   string `mapstructure:"meter"`   // Charge meter reference
+	SiteRef    string `mapstructure:"site"`    // Additional site reference, empty for main site
 
 	Soc             loadpoint.SocConfig
 	Enable, Disable loadpoint.ThresholdConfig
+	Position        loadpoint.PositionConfig
+	Health          loadpoint.HealthConfig
+	Adaptive        loadpoint.AdaptiveConfig
+	Thermal         thermal.Config
+	SgReady         sgready.Config
 
 	// from yaml
-	DefaultMode api.ChargeMode `mapstructure:"mode"`     // Default charge mode, used for disconnect
-	Title       string         `mapstructure:"title"`    // UI title
-	Priority    int            `mapstructure:"priority"` // Priority
+	DefaultMode api.ChargeMode `mapstructure:"mode"`      // Default charge mode, used for disconnect
+	Title       string         `mapstructure:"title"`     // UI title
+	Priority    int            `mapstructure:"priority"`  // Priority
+	GridPhase   int            `mapstructure:"gridPhase"` // Grid phase (1-3) used when charging single-phase
+
+	OffGridMode api.ChargeMode `mapstructure:"offGridMode"` // Charge mode in off-grid operation, pv (default) or off
 
 	// from yaml, deprecated
 	GuardDuration_ time.Duration `mapstructure:"guardduration"` // ignored, present for compatibility
@@ -125,6 +141,8 @@ type Loadpoint struct {
 	phases              int       // Charger enabled phases, guarded by mutex
 	measuredPhases      int       // Charger physically measured phases
 	offeredCurrent      float64   // Charger current limit
+	dischargePower      float64   // Vehicle discharge power
+	v2hWarned           bool      // Missing discharger capability logged
 	socUpdated          time.Time // Soc updated timestamp (poll: connected)
 	vehicleDetect       time.Time // Vehicle connected timestamp
 	chargerSwitched     time.Time // Charger enabled/disabled timestamp
@@ -144,6 +162,8 @@ type Loadpoint struct {
 	coordinator    coordinator.API
 	socEstimator   *soc.Estimator
 
+	vehicleConfirmed bool // active vehicle identified by charger id, vehicle status or user selection
+
 	// charge planning
 	planner          *planner.Planner
 	planTime         time.Time        // time goal
@@ -155,17 +175,32 @@ type Loadpoint struct {
 	planOverrunSent  bool             // notification has been sent already
 	planLocked       PlanLock         // locked plan
 
+	// scheduled changes
+	schedule schedule.Schedule // scheduled setting changes
+
 	// cached state
-	status         api.ChargeStatus // Charger status
-	chargePower    float64          // Charging power
-	chargeCurrents []float64        // Phase currents
-	connectedTime  time.Time        // Time when vehicle was connected
-	pvTimer        time.Time        // PV enabled/disable timer
-	phaseTimer     time.Time        // 1p3p switch timer
-	wakeUpTimer    *Timer           // Vehicle wake-up timeout
+	status         api.ChargeStatus     // Charger status
+	chargePower    float64              // Charging power
+	chargeCurrents []float64            // Phase currents
+	fingerprint    fingerprint.Observer // Charge behavior for identifying vehicles
+	connectedTime  time.Time            // Time when vehicle was connected
+	pvTimer        time.Time            // PV enabled/disable timer
+	phaseTimer     time.Time            // 1p3p switch timer
+	wakeUpTimer    *Timer               // Vehicle wake-up timeout
+	health         *health.Monitor      // Charger health
+	adaptive       *adaptive.Adapter    // PV mode threshold adaptation
+	adapted        *adaptive.Decision   // PV mode thresholds adapted to volatility
+
+	// thermal storage
+	thermal        *thermal.Model          // Thermal storage model of heating devices
+	thermalOutside func() (float64, error) // Outside temperature
+
+	sgready *sgready.Controller // SG-Ready state scheduling
 
 	// charge progress
 	vehicleSoc              float64       // Vehicle or charger soc
+	vehicleRange            int64         // Vehicle range
+	vehicleLimitSoc         int64         // Vehicle or charger limit soc, 0 if unknown
 	chargeDuration          time.Duration // Charge duration
 	connectedDuration       time.Duration // Connection duration
 	energyMetrics           EnergyMetrics // Stats for charged energy by session
@@ -205,6 +240,14 @@ func NewLoadpointFromConfig(log *util.Logger, settings settings.Settings, other
 		lp.log.WARN.Printf("PV mode enable threshold %.0fW > 0 will start PV charging on grid power consumption. Did you mean -%.0f?", lp.Enable.Threshold, lp.Enable.Threshold)
 	}
 
+	switch lp.OffGridMode {
+	case "":
+		lp.OffGridMode = api.ModePV
+	case api.ModePV, api.ModeOff:
+	default:
+		return lp, fmt.Errorf("invalid off-grid mode: %s", lp.OffGridMode)
+	}
+
 	// choose sane default if mode is not set
 	if lp.mode = lp.DefaultMode; lp.mode == "" {
 		lp.mode = api.ModeOff
@@ -218,6 +261,14 @@ func NewLoadpointFromConfig(log *util.Logger, settings settings.Settings, other
 		lp.setPriority(lp.Priority)
 	}
 
+	if lp.Position.Configured() && lp.Position.Radius == 0 {
+		lp.Position.Radius = 100 // m
+	}
+
+	if lp.GridPhase < 0 || lp.GridPhase > 3 {
+		return lp, fmt.Errorf("invalid grid phase: %d", lp.GridPhase)
+	}
+
 	if lp.CircuitRef != "" {
 		dev, err := config.Circuits().ByName(lp.CircuitRef)
 		if err != nil {
@@ -267,6 +318,25 @@ func NewLoadpointFromConfig(log *util.Logger, settings settings.Settings, other
 
 	lp.configureChargerType(lp.charger)
 
+	lp.health = health.NewMonitor(lp.clock, lp.Health.Threshold, lp.Health.Timeout, len(lp.Health.Recovery))
+
+	if lp.Adaptive.Configured() {
+		lp.adaptive = adaptive.New(lp.clock, lp.Adaptive)
+	}
+
+	if lp.Thermal.Configured() {
+		if err := lp.configureThermal(); err != nil {
+			return lp, fmt.Errorf("thermal: %w", err)
+		}
+	}
+
+	if lp.SgReady.Configured() {
+		if _, ok := api.Cap[api.SgReady](lp.charger); !ok {
+			return lp, errors.New("sgready: charger does not support sg-ready states")
+		}
+		lp.sgready = sgready.New(lp.clock, lp.SgReady)
+	}
+
 	// phase switching defaults based on charger capabilities
 	if !lp.hasPhaseSwitching() {
 		phases := lp.getChargerPhysicalPhases()
@@ -385,6 +455,11 @@ func (lp *Loadpoint) restoreSettings() {
 	if err := lp.settings.Json(keys.PlanStrategy, &planStrategy); err == nil {
 		lp.setPlanStrategy(planStrategy)
 	}
+
+	var changes []api.ScheduledChange
+	if err := lp.settings.Json(keys.Schedule, &changes); err == nil {
+		lp.schedule.Restore(changes)
+	}
 }
 
 // requestUpdate requests site to update this loadpoint
@@ -573,6 +648,10 @@ func (lp *Loadpoint) evVehicleDisconnectHandler() {
 	// set default mode on disconnect
 	lp.defaultMode()
 
+	// learn charge behavior of identified vehicle
+	lp.learnFingerprint()
+	lp.publish(keys.VehicleMatchConfidence, 0.0)
+
 	// set default vehicle (may be nil)
 	lp.setActiveVehicle(lp.defaultVehicle)
 
@@ -680,6 +759,7 @@ func (lp *Loadpoint) Prepare(site site.API, uiChan chan<- util.Param, pushChan c
 	lp.publish(keys.PhasesActive, lp.ActivePhases())
 	lp.publish(keys.SmartCostLimit, lp.smartCostLimit)
 	lp.publish(keys.SmartFeedInPriorityLimit, lp.smartFeedInPriorityLimit)
+	lp.schedule.Publish(lp.scheduleOwner(), lp.clock.Now())
 	lp.publishTimer(phaseTimer, 0, timerInactive)
 	lp.publishTimer(pvTimer, 0, timerInactive)
 
@@ -898,7 +978,11 @@ func (lp *Loadpoint) setLimit(current float64) error {
 			actualCurrent = lp.offeredCurrent
 		}
 
-		currentLimit := lp.circuit.ValidateCurrent(actualCurrent, current)
+		lp.RLock()
+		phases := lp.circuitPhases()
+		lp.RUnlock()
+
+		currentLimit := lp.circuit.ValidatePhaseCurrent(phases, actualCurrent, current)
 
 		activePhases := lp.ActivePhases()
 		powerLimit := lp.circuit.ValidatePower(lp.chargePower, currentToPower(current, activePhases))
@@ -960,6 +1044,10 @@ func (lp *Loadpoint) setLimit(current float64) error {
 		lp.setAndPublishEnabled(enabled)
 		lp.chargerSwitched = lp.clock.Now()
 
+		if lp.adaptive != nil {
+			lp.adaptive.Switched()
+		}
+
 		// ensure we always re-set current when enabling charger
 		if !enabled {
 			lp.offeredCurrent = 0
@@ -1300,7 +1388,7 @@ func (lp *Loadpoint) fastCharging() error {
 		// load management limit active
 		if lp.circuit != nil {
 			minPower3p := currentToPower(lp.effectiveMinCurrent(), 3)
-			if powerLimit := lp.circuit.ValidatePower(lp.chargePower, minPower3p); powerLimit < minPower3p {
+			if powerLimit := lp.chargePower + lp.circuit.GetRemainingPower(); powerLimit < minPower3p {
 				phases = 1
 				lp.log.DEBUG.Printf("fast charging: scaled to 1p to match %.0fW available circuit power", powerLimit)
 			}
@@ -1460,6 +1548,7 @@ func (lp *Loadpoint) pvMaxCurrent(mode api.ChargeMode, sitePower, batteryBoostPo
 	// read only once to simplify testing
 	minCurrent := lp.effectiveMinCurrent()
 	maxCurrent := lp.effectiveMaxCurrent()
+	thresholds := lp.pvThresholds()
 
 	// push demand to drain battery
 	sitePower -= lp.boostPower(batteryBoostPower)
@@ -1500,18 +1589,18 @@ func (lp *Loadpoint) pvMaxCurrent(mode api.ChargeMode, sitePower, batteryBoostPo
 			projectedSitePower -= Voltage * minCurrent * float64(activePhases-1)
 		}
 		// kick off disable sequence
-		if projectedSitePower >= lp.Disable.Threshold {
-			lp.log.DEBUG.Printf("projected site power %.0fW >= %.0fW disable threshold", projectedSitePower, lp.Disable.Threshold)
+		if projectedSitePower >= thresholds.Disable.Threshold {
+			lp.log.DEBUG.Printf("projected site power %.0fW >= %.0fW disable threshold", projectedSitePower, thresholds.Disable.Threshold)
 
 			if lp.pvTimer.IsZero() {
-				lp.log.DEBUG.Printf("pv disable timer start: %v", lp.GetDisableDelay())
+				lp.log.DEBUG.Printf("pv disable timer start: %v", thresholds.Disable.Delay)
 				lp.pvTimer = lp.clock.Now()
 			}
 
-			lp.publishTimer(pvTimer, lp.GetDisableDelay(), pvDisable)
+			lp.publishTimer(pvTimer, thresholds.Disable.Delay, pvDisable)
 
 			elapsed := lp.clock.Since(lp.pvTimer)
-			if elapsed >= lp.GetDisableDelay() {
+			if elapsed >= thresholds.Disable.Delay {
 				lp.log.DEBUG.Println("pv disable timer elapsed")
 
 				// reset timer to prevent immediate charger re-enabling
@@ -1522,7 +1611,7 @@ func (lp *Loadpoint) pvMaxCurrent(mode api.ChargeMode, sitePower, batteryBoostPo
 
 			// suppress duplicate log message after timer started
 			if elapsed > time.Second {
-				lp.log.DEBUG.Printf("pv disable timer remaining: %v", (lp.GetDisableDelay() - elapsed).Round(time.Second))
+				lp.log.DEBUG.Printf("pv disable timer remaining: %v", (thresholds.Disable.Delay - elapsed).Round(time.Second))
 			}
 		} else {
 			// reset timer
@@ -1535,19 +1624,19 @@ func (lp *Loadpoint) pvMaxCurrent(mode api.ChargeMode, sitePower, batteryBoostPo
 
 	if mode == api.ModePV && !lp.enabled {
 		// kick off enable sequence
-		if (lp.Enable.Threshold == 0 && targetCurrent >= minCurrent) ||
-			(lp.Enable.Threshold != 0 && sitePower <= lp.Enable.Threshold) {
-			lp.log.DEBUG.Printf("site power %.0fW <= %.0fW enable threshold", sitePower, lp.Enable.Threshold)
+		if (thresholds.Enable.Threshold == 0 && targetCurrent >= minCurrent) ||
+			(thresholds.Enable.Threshold != 0 && sitePower <= thresholds.Enable.Threshold) {
+			lp.log.DEBUG.Printf("site power %.0fW <= %.0fW enable threshold", sitePower, thresholds.Enable.Threshold)
 
 			if lp.pvTimer.IsZero() {
-				lp.log.DEBUG.Printf("pv enable timer start: %v", lp.GetEnableDelay())
+				lp.log.DEBUG.Printf("pv enable timer start: %v", thresholds.Enable.Delay)
 				lp.pvTimer = lp.clock.Now()
 			}
 
-			lp.publishTimer(pvTimer, lp.GetEnableDelay(), pvEnable)
+			lp.publishTimer(pvTimer, thresholds.Enable.Delay, pvEnable)
 
 			elapsed := lp.clock.Since(lp.pvTimer)
-			if elapsed >= lp.GetEnableDelay() {
+			if elapsed >= thresholds.Enable.Delay {
 				lp.log.DEBUG.Println("pv enable timer elapsed")
 
 				// reset timer to prevent immediate charger re-disabling
@@ -1558,7 +1647,7 @@ func (lp *Loadpoint) pvMaxCurrent(mode api.ChargeMode, sitePower, batteryBoostPo
 
 			// suppress duplicate log message after timer started
 			if elapsed > time.Second {
-				lp.log.DEBUG.Printf("pv enable timer remaining: %v", (lp.GetEnableDelay() - elapsed).Round(time.Second))
+				lp.log.DEBUG.Printf("pv enable timer remaining: %v", (thresholds.Enable.Delay - elapsed).Round(time.Second))
 			}
 		} else {
 			// reset timer
@@ -1788,6 +1877,7 @@ func (lp *Loadpoint) publishSocAndRange() {
 		if vs, ok := api.Cap[api.VehicleRange](lp.GetVehicle()); ok {
 			if rng, err := vs.Range(); err == nil {
 				lp.log.DEBUG.Printf("vehicle range: %dkm", rng)
+				lp.vehicleRange = rng
 				lp.publish(keys.VehicleRange, rng)
 			} else if !loadpoint.AcceptableError(err) {
 				lp.log.ERROR.Printf("vehicle range: %v", err)
@@ -1807,6 +1897,7 @@ func (lp *Loadpoint) publishSocAndRange() {
 
 	apiLimitSoc := 100
 	if limitR != nil {
+		lp.vehicleLimitSoc = *limitR
 		apiLimitSoc = int(*limitR)
 		// https://github.com/evcc-io/evcc/issues/13349
 		lp.publish(keys.VehicleLimitSoc, float64(*limitR))
@@ -1879,16 +1970,19 @@ func (lp *Loadpoint) shouldBeConsistent() bool {
 
 // chargerUpdateCompleted returns true if enable command should be already processed by the charger (so we can try to sync charger and loadpoint)
 func (lp *Loadpoint) chargerUpdateCompleted() bool {
-	return time.Since(lp.chargerSwitched) > chargerSwitchDuration
+	return lp.clock.Since(lp.chargerSwitched) > chargerSwitchDuration
 }
 
 // phaseSwitchCompleted returns true if phase switch command should be already processed by the charger (so we can try to sync charger and loadpoint and are able to measure currents)
 func (lp *Loadpoint) phaseSwitchCompleted() bool {
-	return time.Since(lp.phasesSwitched) > phaseSwitchDuration
+	return lp.clock.Since(lp.phasesSwitched) > phaseSwitchDuration
 }
 
 // Update is the main control function. It reevaluates meters and charger state
 func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, feedin api.Rates, batteryBuffered, batteryStart bool, greenShare float64, effPrice, effCo2 *float64) {
+	// scheduled setting changes
+	lp.applySchedule()
+
 	// auto-disable battery boost when SOC drops below limit
 	if lp.GetBatteryBoost() != boostDisabled {
 		if limit := lp.GetBatteryBoostLimit(); limit < 100 {
@@ -1928,8 +2022,8 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 	lp.publishChargeProgress()
 	lp.PublishEffectiveValues()
 
-	// §14a
-	if dimmer, ok := api.Cap[api.Dimmer](lp.charger); ok {
+	// §14a, handled by sg-ready state scheduling if configured
+	if dimmer, ok := api.Cap[api.Dimmer](lp.charger); ok && lp.sgready == nil {
 		dimmed, err := dimmer.Dimmed()
 		if err != nil {
 			lp.log.ERROR.Printf("dimmed: %v", err)
@@ -1957,6 +2051,7 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 	welcomeCharge, err := lp.updateChargerStatus()
 	if err != nil {
 		lp.log.ERROR.Println(err)
+		lp.updateHealth(err)
 		return
 	}
 
@@ -1983,6 +2078,12 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 		if lp.vehicleUnidentified() {
 			lp.identifyVehicleByStatus()
 		}
+
+		// find vehicle by charge behavior
+		lp.updateFingerprint()
+		if lp.vehicleUnidentified() {
+			lp.identifyVehicleByFingerprint()
+		}
 	}
 
 	// publish soc after updating charger status to make sure
@@ -1992,6 +2093,7 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 	// sync settings with charger
 	if err := lp.syncCharger(); err != nil {
 		lp.log.ERROR.Println(err)
+		lp.updateHealth(err)
 		return
 	}
 
@@ -2005,6 +2107,23 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 	minSocNotReached := lp.minSocNotReached()
 	lp.publish(keys.MinSocNotReached, minSocNotReached)
 
+	// update and publish departure guarantee state
+	departureRequired := lp.departureGuaranteeRequired()
+
+	// update and publish thermal storage comfort state
+	thermalRequired := lp.thermalRequired()
+
+	// vehicle-to-home discharging
+	v2h := mode == api.ModeV2H && lp.connected() && !minSocNotReached && !plannerActive && !departureRequired && !thermalRequired
+	if !v2h {
+		if err := lp.setDischarge(0); err != nil {
+			lp.log.ERROR.Println(err)
+		}
+	}
+
+	// adapt pv mode thresholds to volatility
+	lp.adaptThresholds(sitePower)
+
 	// execute loading strategy
 	switch {
 	case !lp.connected():
@@ -2012,6 +2131,10 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 		// https://github.com/evcc-io/evcc/issues/105
 		err = lp.setLimit(0)
 
+	// sg-ready state scheduling replaces the charging strategy
+	case lp.sgready != nil:
+		err = lp.updateSgReady(sitePower)
+
 	case lp.scalePhasesRequired():
 		err = lp.scalePhases(lp.phasesConfigured)
 
@@ -2022,12 +2145,19 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 		}
 		err = lp.setLimit(current)
 
+	// off-grid operation limited to pv surplus without battery support
+	case lp.offGridActive() && !v2h:
+		err = lp.offGridCharging(sitePower)
+
 	// minimum or target charging
-	case minSocNotReached || plannerActive:
+	case minSocNotReached || plannerActive || departureRequired || thermalRequired:
 		err = lp.fastCharging()
 		lp.resetPhaseTimer()
 		lp.elapsePVTimer() // let PV mode disable immediately afterwards
 
+	case v2h:
+		err = lp.dischargeVehicle(sitePower)
+
 	case lp.LimitEnergyReached():
 		lp.log.DEBUG.Printf("limitEnergy reached: %.0fkWh > %0.1fkWh", lp.GetChargedEnergy()/1e3, lp.limitEnergy)
 		err = lp.disableUnlessClimater()
@@ -2036,6 +2166,10 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 		lp.log.DEBUG.Printf("limitSoc reached: %.1f%% > %d%%", lp.vehicleSoc, lp.EffectiveLimitSoc())
 		err = lp.disableUnlessClimater()
 
+	case lp.thermalLimitReached():
+		lp.log.DEBUG.Printf("thermal: maxTemp reached: %.1f°C >= %.1f°C", lp.vehicleSoc, lp.thermal.MaxTemp())
+		err = lp.disableUnlessClimater()
+
 	// immediate charging- must be placed after limits are evaluated
 	case mode == api.ModeNow:
 		err = lp.fastCharging()
@@ -2043,7 +2177,7 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 	case mode == api.ModeMinPV || mode == api.ModePV:
 		// cheap tariff
 		if smartCostActive {
-			rate, _ := consumption.At(time.Now())
+			rate, _ := consumption.At(lp.clock.Now())
 			lp.log.DEBUG.Printf("smart consumption active: %.2f", rate.Value)
 			err = lp.fastCharging()
 			lp.resetPhaseTimer()
@@ -2053,7 +2187,7 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 
 		// attractive feedin
 		if smartFeedInPriorityActive {
-			rate, _ := feedin.At(time.Now())
+			rate, _ := feedin.At(lp.clock.Now())
 			lp.log.DEBUG.Printf("smart feed-in active: %.2f", rate.Value)
 
 			var targetCurrent float64
@@ -2103,4 +2237,7 @@ func (lp *Loadpoint) Update(sitePower, batteryBoostPower float64, consumption, f
 	if err != nil {
 		lp.log.ERROR.Println(err)
 	}
+
+	// track charger health and recover if necessary
+	lp.updateHealth(err)
 }
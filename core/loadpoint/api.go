@@ -82,6 +82,8 @@ type API interface {
 	SetPhasesConfigured(int) error
 	// ActivePhases returns the active phases for the current vehicle
 	ActivePhases() int
+	// GetGridPhase returns the grid phase used when charging single-phase, 0 if unknown
+	GetGridPhase() int
 
 	// GetLimitSoc returns the session limit soc
 	GetLimitSoc() int
@@ -136,6 +138,15 @@ type API interface {
 	// GetPlan creates a charging plan
 	GetPlan(targetTime time.Time, requiredDuration, precondition time.Duration, continuous bool) api.Rates
 
+	//
+	// scheduled changes
+	//
+
+	// GetSchedule returns the scheduled setting changes
+	GetSchedule() []api.ScheduledChange
+	// SetSchedule sets the scheduled setting changes
+	SetSchedule([]api.ScheduledChange) error
+
 	// GetSocConfig returns the soc poll settings
 	GetSocConfig() SocConfig
 	// SetSocConfig sets the soc poll settings
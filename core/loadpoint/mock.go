@@ -347,6 +347,20 @@ func (mr *MockAPIMockRecorder) GetEnableThreshold() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnableThreshold", reflect.TypeOf((*MockAPI)(nil).GetEnableThreshold))
 }
 
+// GetGridPhase mocks base method.
+func (m *MockAPI) GetGridPhase() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGridPhase")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetGridPhase indicates an expected call of GetGridPhase.
+func (mr *MockAPIMockRecorder) GetGridPhase() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGridPhase", reflect.TypeOf((*MockAPI)(nil).GetGridPhase))
+}
+
 // GetLimitEnergy mocks base method.
 func (m *MockAPI) GetLimitEnergy() float64 {
 	m.ctrl.T.Helper()
@@ -587,6 +601,20 @@ func (mr *MockAPIMockRecorder) GetRemainingEnergy() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemainingEnergy", reflect.TypeOf((*MockAPI)(nil).GetRemainingEnergy))
 }
 
+// GetSchedule mocks base method.
+func (m *MockAPI) GetSchedule() []api.ScheduledChange {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule")
+	ret0, _ := ret[0].([]api.ScheduledChange)
+	return ret0
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockAPIMockRecorder) GetSchedule() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockAPI)(nil).GetSchedule))
+}
+
 // GetSmartCostLimit mocks base method.
 func (m *MockAPI) GetSmartCostLimit() *float64 {
 	m.ctrl.T.Helper()
@@ -991,6 +1019,20 @@ func (mr *MockAPIMockRecorder) SetPriority(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPriority", reflect.TypeOf((*MockAPI)(nil).SetPriority), arg0)
 }
 
+// SetSchedule mocks base method.
+func (m *MockAPI) SetSchedule(arg0 []api.ScheduledChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSchedule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSchedule indicates an expected call of SetSchedule.
+func (mr *MockAPIMockRecorder) SetSchedule(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSchedule", reflect.TypeOf((*MockAPI)(nil).SetSchedule), arg0)
+}
+
 // SetSmartCostLimit mocks base method.
 func (m *MockAPI) SetSmartCostLimit(limit *float64) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,98 @@
+// Code generated by "enumer -type RecoveryAction -trimprefix Recovery -transform=lower -text"; DO NOT EDIT.
+
+package loadpoint
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _RecoveryActionName = "currentenablewakeupreboot"
+
+var _RecoveryActionIndex = [...]uint8{0, 7, 13, 19, 25}
+
+const _RecoveryActionLowerName = "currentenablewakeupreboot"
+
+func (i RecoveryAction) String() string {
+	if i < 0 || i >= RecoveryAction(len(_RecoveryActionIndex)-1) {
+		return fmt.Sprintf("RecoveryAction(%d)", i)
+	}
+	return _RecoveryActionName[_RecoveryActionIndex[i]:_RecoveryActionIndex[i+1]]
+}
+
+// An "invalid array index" compiler error signifies that the constant values have changed.
+// Re-run the stringer command to generate them again.
+func _RecoveryActionNoOp() {
+	var x [1]struct{}
+	_ = x[RecoveryCurrent-(0)]
+	_ = x[RecoveryEnable-(1)]
+	_ = x[RecoveryWakeUp-(2)]
+	_ = x[RecoveryReboot-(3)]
+}
+
+var _RecoveryActionValues = []RecoveryAction{RecoveryCurrent, RecoveryEnable, RecoveryWakeUp, RecoveryReboot}
+
+var _RecoveryActionNameToValueMap = map[string]RecoveryAction{
+	_RecoveryActionName[0:7]:        RecoveryCurrent,
+	_RecoveryActionLowerName[0:7]:   RecoveryCurrent,
+	_RecoveryActionName[7:13]:       RecoveryEnable,
+	_RecoveryActionLowerName[7:13]:  RecoveryEnable,
+	_RecoveryActionName[13:19]:      RecoveryWakeUp,
+	_RecoveryActionLowerName[13:19]: RecoveryWakeUp,
+	_RecoveryActionName[19:25]:      RecoveryReboot,
+	_RecoveryActionLowerName[19:25]: RecoveryReboot,
+}
+
+var _RecoveryActionNames = []string{
+	_RecoveryActionName[0:7],
+	_RecoveryActionName[7:13],
+	_RecoveryActionName[13:19],
+	_RecoveryActionName[19:25],
+}
+
+// RecoveryActionString retrieves an enum value from the enum constants string name.
+// Throws an error if the param is not part of the enum.
+func RecoveryActionString(s string) (RecoveryAction, error) {
+	if val, ok := _RecoveryActionNameToValueMap[s]; ok {
+		return val, nil
+	}
+
+	if val, ok := _RecoveryActionNameToValueMap[strings.ToLower(s)]; ok {
+		return val, nil
+	}
+	return 0, fmt.Errorf("%s does not belong to RecoveryAction values", s)
+}
+
+// RecoveryActionValues returns all values of the enum
+func RecoveryActionValues() []RecoveryAction {
+	return _RecoveryActionValues
+}
+
+// RecoveryActionStrings returns a slice of all String values of the enum
+func RecoveryActionStrings() []string {
+	strs := make([]string, len(_RecoveryActionNames))
+	copy(strs, _RecoveryActionNames)
+	return strs
+}
+
+// IsARecoveryAction returns "true" if the value is listed in the enum definition. "false" otherwise
+func (i RecoveryAction) IsARecoveryAction() bool {
+	for _, v := range _RecoveryActionValues {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for RecoveryAction
+func (i RecoveryAction) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for RecoveryAction
+func (i *RecoveryAction) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = RecoveryActionString(string(text))
+	return err
+}
@@ -1,6 +1,7 @@
 package loadpoint
 
 import (
+	"math"
 	"time"
 )
 
@@ -22,6 +23,51 @@ type SocConfig struct {
 	Estimate *bool      `json:"estimate"`
 }
 
+// PositionConfig defines the loadpoint's location for identifying vehicles by position
+type PositionConfig struct {
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	Radius float64 `json:"radius"` // m
+}
+
+// Configured returns true if the position is configured
+func (p PositionConfig) Configured() bool {
+	return p.Lat != 0 || p.Lon != 0
+}
+
+// Distance returns the great-circle distance to the given position in m
+func (p PositionConfig) Distance(lat, lon float64) float64 {
+	const earthRadius = 6371e3 // m
+
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := rad(lat - p.Lat)
+	dLon := rad(lon - p.Lon)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(p.Lat))*math.Cos(rad(lat))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// AdaptiveConfig defines the bounds for adapting pv mode thresholds and delays to volatility
+type AdaptiveConfig struct {
+	MaxSwitches int           `json:"maxSwitches"` // charger switches per hour considered excessive
+	MaxDelay    time.Duration `json:"maxDelay"`    // upper bound for enable and disable delays
+	Hysteresis  float64       `json:"hysteresis"`  // upper bound for additional hysteresis in W
+}
+
+// Configured returns true if adaptive thresholds are configured
+func (c AdaptiveConfig) Configured() bool {
+	return c.MaxDelay > 0 || c.Hysteresis > 0
+}
+
+// HealthConfig defines charger health monitoring and recovery
+type HealthConfig struct {
+	Threshold int              `json:"threshold"` // consecutive faulty cycles until degraded
+	Timeout   time.Duration    `json:"timeout"`   // charging without power until stalled, delay between recovery actions
+	Recovery  []RecoveryAction `json:"recovery"`  // recovery actions in order of execution
+}
+
 // PollConfig defines the vehicle polling mode and interval
 type PollConfig struct {
 	Mode     PollMode      `json:"mode"`     // polling mode charging (default), connected, always
@@ -37,3 +83,14 @@ const (
 	PollConnected
 	PollAlways
 )
+
+//go:generate go tool enumer -type RecoveryAction -trimprefix Recovery -transform=lower -text
+type RecoveryAction int
+
+// Recovery actions
+const (
+	RecoveryCurrent RecoveryAction = iota // re-send charge current
+	RecoveryEnable                        // toggle charger enable
+	RecoveryWakeUp                        // wake up charger or vehicle
+	RecoveryReboot                        // reboot charger
+)
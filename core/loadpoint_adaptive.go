@@ -0,0 +1,34 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+// adaptThresholds adapts the pv mode thresholds to the volatility of the available power and the charger switching frequency
+func (lp *Loadpoint) adaptThresholds(sitePower float64) {
+	if lp.adaptive == nil {
+		return
+	}
+
+	// power available to the loadpoint, combining pv and household fluctuations
+	lp.adaptive.Sample(lp.chargePower - sitePower)
+
+	decision := lp.adaptive.Adapt(lp.GetThresholds(), lp.EffectiveMinPower())
+
+	if lp.adapted == nil || decision.ThresholdsConfig != lp.adapted.ThresholdsConfig {
+		lp.log.DEBUG.Printf("adaptive thresholds: enable %.0fW/%v, disable %.0fW/%v (volatility: %.0fW, switches: %d)",
+			decision.Enable.Threshold, decision.Enable.Delay, decision.Disable.Threshold, decision.Disable.Delay, decision.Volatility, decision.Switches)
+	}
+
+	lp.adapted = &decision
+	lp.publish(keys.AdaptiveThresholds, decision)
+}
+
+// pvThresholds returns the pv mode thresholds, adapted to volatility if configured
+func (lp *Loadpoint) pvThresholds() loadpoint.ThresholdsConfig {
+	if lp.adapted != nil {
+		return lp.adapted.ThresholdsConfig
+	}
+	return lp.GetThresholds()
+}
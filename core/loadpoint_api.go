@@ -222,6 +222,11 @@ func (lp *Loadpoint) GetPriority() int {
 	return lp.priority
 }
 
+// GetGridPhase returns the grid phase used when charging single-phase, 0 if unknown
+func (lp *Loadpoint) GetGridPhase() int {
+	return lp.GridPhase
+}
+
 // setPriority sets the loadpoint priority (no mutex)
 func (lp *Loadpoint) setPriority(prio int) {
 	lp.priority = prio
@@ -677,6 +682,32 @@ func (lp *Loadpoint) GetMaxPhaseCurrent() float64 {
 	return max(lp.chargeCurrents[0], lp.chargeCurrents[1], lp.chargeCurrents[2])
 }
 
+// GetPhaseCurrents returns the charge currents per grid phase or- if not available-
+// the offered current on the phases used by the loadpoint
+func (lp *Loadpoint) GetPhaseCurrents() [3]float64 {
+	lp.RLock()
+	defer lp.RUnlock()
+
+	phases := lp.circuitPhases()
+	if lp.chargeCurrents != nil && phases == [3]bool{true, true, true} {
+		return [3]float64(lp.chargeCurrents)
+	}
+
+	current := lp.offeredCurrent
+	if lp.chargeCurrents != nil {
+		// single-phase charging on a known grid phase, measured in charger phase order
+		current = max(lp.chargeCurrents[0], lp.chargeCurrents[1], lp.chargeCurrents[2])
+	}
+
+	var res [3]float64
+	for i, used := range phases {
+		if used {
+			res[i] = current
+		}
+	}
+	return res
+}
+
 // GetMinCurrent returns the min loadpoint current
 func (lp *Loadpoint) GetMinCurrent() float64 {
 	lp.RLock()
@@ -812,6 +843,7 @@ func (lp *Loadpoint) GetVehicle() api.Vehicle {
 func (lp *Loadpoint) SetVehicle(vehicle api.Vehicle) {
 	// set desired vehicle (protected by lock, no locking here)
 	lp.setActiveVehicle(vehicle)
+	lp.confirmVehicle()
 
 	lp.vmu.Lock()
 	defer lp.vmu.Unlock()
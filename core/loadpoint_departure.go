@@ -0,0 +1,87 @@
+package core
+
+import (
+	"cmp"
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/vehicle"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+)
+
+// departureSafetyFactor reserves additional charging time in case charging is slower than estimated
+const departureSafetyFactor = 1.25
+
+// departureTarget returns the next departure and the soc required by the vehicle's departure guarantee
+func (lp *Loadpoint) departureTarget() (time.Time, int) {
+	v := lp.GetVehicle()
+	if v == nil {
+		return time.Time{}, 0
+	}
+
+	g := vehicle.Settings(lp.log, v).GetDepartureGuarantee()
+	if !g.Active || len(g.Weekdays) == 0 {
+		return time.Time{}, 0
+	}
+
+	departure, err := util.NextOccurrence(lp.clock.Now(), g.Weekdays, g.Time, cmp.Or(g.Tz, "Local"))
+	if err != nil {
+		lp.log.DEBUG.Printf("invalid departure guarantee: weekdays=%v, time=%s, tz=%s, error=%v", g.Weekdays, g.Time, g.Tz, err)
+		return time.Time{}, 0
+	}
+
+	soc := g.Soc
+
+	// convert range to soc using the vehicle's current range per soc
+	if g.Range > 0 {
+		if lp.vehicleRange > 0 && lp.vehicleSoc > 0 {
+			soc = max(soc, int(math.Ceil(float64(g.Range)*lp.vehicleSoc/float64(lp.vehicleRange))))
+		} else {
+			lp.log.DEBUG.Printf("departure guarantee: unknown vehicle range for %dkm", g.Range)
+		}
+	}
+
+	return departure, min(soc, 100)
+}
+
+// departureGuaranteeRequired checks if charging is required for reaching the departure guarantee.
+// While the guaranteed soc is comfortably reachable, charging is left to pv and smart cost.
+// Once the deadline requires it, the cheapest remaining slots until departure are used.
+func (lp *Loadpoint) departureGuaranteeRequired() (required bool) {
+	var target int
+
+	defer func() {
+		lp.publish(keys.DepartureGuaranteeSoc, target)
+		lp.publish(keys.DepartureGuaranteeActive, required)
+	}()
+
+	if !lp.connected() || !lp.socBasedPlanning() || lp.vehicleSoc == 0 {
+		return false
+	}
+
+	var departure time.Time
+	if departure, target = lp.departureTarget(); target == 0 || lp.vehicleSoc >= float64(target) {
+		return false
+	}
+
+	duration := time.Duration(float64(lp.GetPlanRequiredDuration(float64(target), lp.EffectiveMaxPower())) * departureSafetyFactor)
+	remaining := lp.clock.Until(departure)
+
+	if remaining > duration+tariff.SlotDuration {
+		lp.log.DEBUG.Printf("departure guarantee: %d%% by %v reachable, charging from pv", target, departure.Round(time.Minute).Local())
+		return false
+	}
+
+	// charge in cheapest slots unless the deadline has been reached
+	if plan := lp.GetPlan(departure, duration, 0, false); remaining > duration && planner.SlotAt(lp.clock.Now(), plan).End.IsZero() {
+		lp.log.DEBUG.Printf("departure guarantee: %d%% by %v, waiting for planned slot", target, departure.Round(time.Minute).Local())
+		return false
+	}
+
+	lp.log.DEBUG.Printf("departure guarantee: charging to %d%% by %v", target, departure.Round(time.Minute).Local())
+
+	return true
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/core/vehicle"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDepartureGuarantee(t *testing.T) {
+	Voltage = 230
+
+	ctrl := gomock.NewController(t)
+
+	clock := clock.NewMock()
+	clock.Set(time.Date(2025, 6, 2, 20, 0, 0, 0, time.Local)) // Monday
+
+	v := api.NewMockVehicle(ctrl)
+	v.EXPECT().Capacity().Return(50.0).AnyTimes()
+	v.EXPECT().GetTitle().Return("test").AnyTimes()
+	v.EXPECT().Features().Return(nil).AnyTimes()
+	v.EXPECT().OnIdentified().Return(api.ActionConfig{}).AnyTimes()
+	v.EXPECT().Phases().Return(0).AnyTimes()
+
+	dev := config.NewStaticDevice(config.Named{Name: "departure"}, api.Vehicle(v))
+	require.NoError(t, config.Vehicles().Add(dev))
+	t.Cleanup(func() { _ = config.Vehicles().Delete("departure") })
+
+	// empty timezone defaults to local time
+	require.NoError(t, vehicle.Adapter(util.NewLogger("foo"), dev).SetDepartureGuarantee(api.DepartureGuarantee{
+		Weekdays: []int{2}, Time: "07:00", Soc: 80, Active: true,
+	}))
+
+	lp := NewLoadpoint(util.NewLogger("foo"), settings.NewDatabaseSettingsAdapter("foo"))
+	lp.clock = clock
+	lp.charger = api.NewMockCharger(ctrl)
+	lp.vehicle = v
+	lp.status = api.StatusB
+	lp.phases = 3
+	lp.maxCurrent = 16
+	lp.vehicleSoc = 20
+
+	departure, soc := lp.departureTarget()
+	assert.Equal(t, time.Date(2025, 6, 3, 7, 0, 0, 0, time.Local), departure)
+	assert.Equal(t, 80, soc)
+
+	// range converted to soc
+	lp.vehicleRange = 100
+	require.NoError(t, vehicle.Adapter(util.NewLogger("foo"), dev).SetDepartureGuarantee(api.DepartureGuarantee{
+		Weekdays: []int{2}, Time: "07:00", Tz: "Local", Soc: 80, Range: 450, Active: true,
+	}))
+	_, soc = lp.departureTarget()
+	assert.Equal(t, 90, soc)
+
+	// deadline far away, charge from pv
+	assert.False(t, lp.departureGuaranteeRequired())
+
+	// deadline close
+	clock.Set(time.Date(2025, 6, 3, 4, 0, 0, 0, time.Local))
+	assert.True(t, lp.departureGuaranteeRequired())
+
+	// target reached
+	lp.vehicleSoc = 90
+	assert.False(t, lp.departureGuaranteeRequired())
+}
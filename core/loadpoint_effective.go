@@ -250,6 +250,32 @@ func (lp *Loadpoint) effectiveMaxPower() float64 {
 	return res
 }
 
+// effectivePVHeadroom returns the additional power the loadpoint can absorb by raising its current while charging from pv
+func (lp *Loadpoint) effectivePVHeadroom() float64 {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if !lp.enabled || lp.status != api.StatusC || lp.mode != api.ModePV && lp.mode != api.ModeMinPV {
+		return 0
+	}
+
+	return max(0, currentToPower(lp.effectiveMaxCurrent()-lp.offeredCurrent, lp.activePhases()))
+}
+
+// effectivePVStartPower returns the power a connected loadpoint waiting for pv surplus requires to start charging and its enable delay
+func (lp *Loadpoint) effectivePVStartPower() (float64, time.Duration) {
+	lp.Lock()
+	waiting := !lp.enabled && (lp.status == api.StatusB || lp.status == api.StatusC) && (lp.mode == api.ModePV || lp.mode == api.ModeMinPV)
+	power := currentToPower(lp.effectiveMinCurrent(), lp.minActivePhases())
+	lp.Unlock()
+
+	if !waiting {
+		return 0, 0
+	}
+
+	return power, lp.pvThresholds().Enable.Delay
+}
+
 // EffectivePlanStrategy returns the effective plan strategy
 func (lp *Loadpoint) EffectivePlanStrategy() api.PlanStrategy {
 	lp.RLock()
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/fingerprint"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/vehicle"
+)
+
+// fingerprintConfidence is the confidence required for identifying a vehicle by charge behavior
+const fingerprintConfidence = 0.7
+
+// updateFingerprint records the charge behavior while charging
+func (lp *Loadpoint) updateFingerprint() {
+	if !lp.charging() {
+		return
+	}
+
+	phases := lp.GetMeasuredPhases()
+	if phases == 0 {
+		phases = lp.ActivePhases()
+	}
+
+	current := powerToCurrent(lp.chargePower, phases)
+	if lp.chargeCurrents != nil {
+		current = max(lp.chargeCurrents[0], lp.chargeCurrents[1], lp.chargeCurrents[2])
+	}
+
+	lp.fingerprint.Update(lp.clock.Now(), lp.chargePower, current, lp.offeredCurrent, lp.effectiveMaxCurrent(), phases)
+}
+
+// identifyVehicleByFingerprint finds the available vehicle matching the recorded charge behavior
+func (lp *Loadpoint) identifyVehicleByFingerprint() {
+	sig, ok := lp.fingerprint.Signature()
+	if !ok {
+		return
+	}
+
+	vehicles := make(map[string]api.Vehicle)
+	profiles := make(map[string]fingerprint.Profile)
+
+	for _, v := range lp.coordinatedVehicles() {
+		if owner := lp.coordinator.Owner(v); owner != nil && owner != lp {
+			continue
+		}
+
+		if s := vehicle.Settings(lp.log, v); s.Name() != "" {
+			vehicles[s.Name()] = v
+			profiles[s.Name()] = s.GetFingerprint()
+		}
+	}
+
+	name, confidence := fingerprint.Match(sig, profiles)
+	lp.publish(keys.VehicleMatchConfidence, confidence)
+
+	if name == "" || confidence < fingerprintConfidence {
+		lp.log.DEBUG.Printf("vehicle fingerprint: no match (%.0f%% confidence)", 100*confidence)
+		return
+	}
+
+	lp.log.INFO.Printf("vehicle fingerprint: %s (%.0f%% confidence)", vehicles[name].GetTitle(), 100*confidence)
+
+	lp.stopVehicleDetection()
+	lp.setActiveVehicle(vehicles[name])
+}
+
+// confirmVehicle marks the active vehicle as identified by charger id, vehicle status or user selection
+func (lp *Loadpoint) confirmVehicle() {
+	lp.vmu.Lock()
+	defer lp.vmu.Unlock()
+	lp.vehicleConfirmed = lp.vehicle != nil
+}
+
+// learnFingerprint updates the active vehicle's charge behavior with the recorded session.
+// Default or fingerprint-matched vehicles are not confirmed and don't learn.
+func (lp *Loadpoint) learnFingerprint() {
+	defer lp.fingerprint.Reset()
+
+	lp.vmu.RLock()
+	v, confirmed := lp.vehicle, lp.vehicleConfirmed
+	lp.vmu.RUnlock()
+
+	sig, ok := lp.fingerprint.Signature()
+	if !ok || v == nil || !confirmed {
+		return
+	}
+
+	s := vehicle.Settings(lp.log, v)
+
+	profile := s.GetFingerprint()
+	profile.Learn(sig)
+
+	if err := s.SetFingerprint(profile); err != nil {
+		lp.log.ERROR.Println("vehicle fingerprint:", err)
+	}
+}
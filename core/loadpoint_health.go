@@ -0,0 +1,106 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/health"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+// updateHealth records the control cycle's result, notifies health changes and executes due recovery actions
+func (lp *Loadpoint) updateHealth(err error) {
+	if lp.health == nil {
+		return
+	}
+
+	prev := lp.health.Status().State
+
+	if err != nil && !loadpoint.AcceptableError(err) {
+		lp.health.Error(err)
+	} else if err == nil {
+		// vehicles finishing charging in status C are not stalled
+		lp.health.Charging(lp.enabled && lp.charging() && lp.offeredCurrent > 0 && !lp.chargeComplete(), lp.chargePower)
+	}
+
+	status := lp.health.Status()
+	lp.publish(keys.ChargerHealth, status)
+
+	if status.State != prev {
+		if status.State == health.StateOk {
+			lp.log.INFO.Printf("charger health: %s", status.State)
+		} else {
+			lp.log.WARN.Printf("charger health: %s (errors: %d, stalls: %d, last error: %s)", status.State, status.Errors, status.Stalls, status.LastError)
+		}
+
+		lp.pushEvent(evChargerHealth)
+	}
+
+	if i, ok := lp.health.Recovery(); ok {
+		action := lp.Health.Recovery[i]
+		lp.log.WARN.Printf("charger health: recovery action %s", action)
+
+		if err := lp.recoverCharger(action); err != nil {
+			lp.log.ERROR.Printf("charger health: recovery action %s: %v", action, err)
+		}
+	}
+}
+
+// chargeComplete returns true if the vehicle reached the session or its own limit soc.
+// With unknown soc, charging is considered complete once energy was charged in this session.
+func (lp *Loadpoint) chargeComplete() bool {
+	if lp.vehicleSoc == 0 {
+		return lp.getChargedEnergy() > 0
+	}
+
+	limit := int64(lp.effectiveLimitSoc())
+	if lp.vehicleLimitSoc > 0 {
+		limit = min(limit, lp.vehicleLimitSoc)
+	}
+
+	return lp.vehicleSoc >= float64(limit)
+}
+
+// recoverCharger executes a recovery action
+func (lp *Loadpoint) recoverCharger(action loadpoint.RecoveryAction) error {
+	switch action {
+	case loadpoint.RecoveryCurrent:
+		if lp.offeredCurrent == 0 {
+			return nil
+		}
+		if charger, ok := api.Cap[api.ChargerEx](lp.charger); ok {
+			return charger.MaxCurrentMillis(lp.offeredCurrent)
+		}
+		return lp.charger.MaxCurrent(int64(lp.offeredCurrent))
+
+	case loadpoint.RecoveryEnable:
+		if !lp.enabled {
+			return nil
+		}
+		if err := lp.charger.Enable(false); err != nil {
+			return err
+		}
+		lp.chargerSwitched = lp.clock.Now()
+		return lp.charger.Enable(true)
+
+	case loadpoint.RecoveryWakeUp:
+		if charger, ok := api.Cap[api.Resurrector](lp.charger); ok {
+			return charger.WakeUp()
+		}
+		if vehicle, ok := api.Cap[api.Resurrector](lp.GetVehicle()); ok {
+			return vehicle.WakeUp()
+		}
+		return errors.New("wake-up not supported")
+
+	case loadpoint.RecoveryReboot:
+		if charger, ok := api.Cap[api.Rebooter](lp.charger); ok {
+			return charger.Reboot()
+		}
+		return errors.New("reboot not supported")
+
+	default:
+		return fmt.Errorf("invalid recovery action: %d", action)
+	}
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/health"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthChargeComplete(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		soc             float64
+		vehicleLimitSoc int64
+		chargedEnergy   float64
+		stalls          int
+	}{
+		{"below limit", 50, 0, 0, 1},
+		{"session limit reached", 80, 0, 0, 0},
+		{"vehicle limit reached", 70, 70, 0, 0},
+		{"unknown soc, not charged", 0, 0, 0, 1},
+		{"unknown soc, charged", 0, 0, 5, 0},
+	} {
+		t.Log(tc.name)
+
+		clock := clock.NewMock()
+
+		lp := NewLoadpoint(util.NewLogger("foo"), nil)
+		lp.clock = clock
+		lp.health = health.NewMonitor(clock, 10, time.Minute, 0)
+		lp.enabled = true
+		lp.status = api.StatusC
+		lp.offeredCurrent = 16
+		lp.limitSoc = 80
+		lp.vehicleSoc = tc.soc
+		lp.vehicleLimitSoc = tc.vehicleLimitSoc
+		lp.energyMetrics.Update(tc.chargedEnergy)
+
+		// no power beyond timeout
+		lp.updateHealth(nil)
+		clock.Add(2 * time.Minute)
+		lp.updateHealth(nil)
+
+		assert.Equal(t, tc.stalls, lp.health.Status().Stalls, tc.name)
+	}
+}
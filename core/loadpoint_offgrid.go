@@ -0,0 +1,18 @@
+package core
+
+import "github.com/evcc-io/evcc/api"
+
+// offGridActive returns true if the site is in off-grid operation
+func (lp *Loadpoint) offGridActive() bool {
+	return lp.site != nil && lp.site.GetOffGrid()
+}
+
+// offGridCharging limits charging to pv surplus or disables it depending on the off-grid mode
+func (lp *Loadpoint) offGridCharging(sitePower float64) error {
+	if lp.OffGridMode == api.ModeOff {
+		lp.log.DEBUG.Println("off-grid: charging disabled")
+		return lp.setLimit(0)
+	}
+
+	return lp.setLimit(lp.pvMaxCurrent(api.ModePV, sitePower, 0, false, false))
+}
@@ -99,6 +99,17 @@ func (lp *Loadpoint) activePhases() int {
 	return active
 }
 
+// circuitPhases returns the phases used by the loadpoint. Unless charging single-phase
+// on a known grid phase all phases are assumed to be used.
+func (lp *Loadpoint) circuitPhases() [3]bool {
+	if lp.GridPhase > 0 && lp.activePhases() == 1 {
+		var res [3]bool
+		res[lp.GridPhase-1] = true
+		return res
+	}
+	return [3]bool{true, true, true}
+}
+
 // MinActivePhases returns the minimum number of active phases for the loadpoint.
 func (lp *Loadpoint) MinActivePhases() int {
 	lp.RLock()
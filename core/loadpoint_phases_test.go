@@ -487,7 +487,7 @@ func TestFastChargingCircuitBasedPhaseScaling(t *testing.T) {
 		desc                  string
 		phases                int
 		chargePower           float64
-		availableCircuitPower float64 // ValidatePower return for 3p request
+		availableCircuitPower float64 // charge power plus remaining circuit power
 		expectedPhases        int
 		noCircuit             bool
 	}{
@@ -523,13 +523,11 @@ func TestFastChargingCircuitBasedPhaseScaling(t *testing.T) {
 				circuit := api.NewMockCircuit(ctrl)
 				lp.circuit = circuit
 
-				minPower3p := Voltage * 6 * 3
-
-				// fastCharging call to ValidatePower
-				circuit.EXPECT().ValidatePower(tc.chargePower, minPower3p).Return(tc.availableCircuitPower)
+				// fastCharging call to GetRemainingPower
+				circuit.EXPECT().GetRemainingPower().Return(tc.availableCircuitPower - tc.chargePower)
 
 				// setLimit calls
-				circuit.EXPECT().ValidateCurrent(gomock.Any(), lp.maxCurrent).Return(lp.maxCurrent)
+				circuit.EXPECT().ValidatePhaseCurrent(gomock.Any(), gomock.Any(), lp.maxCurrent).Return(lp.maxCurrent)
 				circuit.EXPECT().ValidatePower(tc.chargePower, float64(tc.expectedPhases)*Voltage*lp.maxCurrent).Return(float64(tc.expectedPhases) * Voltage * lp.maxCurrent)
 			}
 
@@ -550,3 +548,24 @@ func TestFastChargingCircuitBasedPhaseScaling(t *testing.T) {
 		})
 	}
 }
+
+func TestGridPhaseCurrents(t *testing.T) {
+	lp := NewLoadpoint(util.NewLogger("foo"), nil)
+	lp.GridPhase = 2
+	lp.phases = 1
+	lp.phasesConfigured = 1
+	lp.offeredCurrent = 16
+
+	// offered current on grid phase
+	require.Equal(t, [3]float64{0, 16, 0}, lp.GetPhaseCurrents())
+
+	// measured current in charger phase order mapped to grid phase
+	lp.chargeCurrents = []float64{10, 0, 0}
+	require.Equal(t, [3]float64{0, 10, 0}, lp.GetPhaseCurrents())
+
+	// three-phase charging uses measured phases
+	lp.phases = 3
+	lp.phasesConfigured = 3
+	lp.chargeCurrents = []float64{10, 11, 12}
+	require.Equal(t, [3]float64{10, 11, 12}, lp.GetPhaseCurrents())
+}
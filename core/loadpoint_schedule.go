@@ -0,0 +1,48 @@
+package core
+
+import (
+	"strconv"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/schedule"
+)
+
+// scheduleOwner returns the loadpoint settings that can be scheduled and the schedule's persistence
+func (lp *Loadpoint) scheduleOwner() schedule.Owner {
+	return schedule.Owner{
+		Log: lp.log,
+		Settings: map[string]schedule.Setting{
+			keys.Mode:             schedule.Func(api.ChargeModeString, schedule.NoError(lp.SetMode)),
+			keys.LimitSoc:         schedule.Func(strconv.Atoi, schedule.NoError(lp.SetLimitSoc)),
+			keys.LimitEnergy:      schedule.Func(schedule.ParseFloat, schedule.NoError(lp.SetLimitEnergy)),
+			keys.Priority:         schedule.Func(strconv.Atoi, schedule.NoError(lp.SetPriority)),
+			keys.MinCurrent:       schedule.Func(schedule.ParseFloat, lp.SetMinCurrent),
+			keys.MaxCurrent:       schedule.Func(schedule.ParseFloat, lp.SetMaxCurrent),
+			keys.PhasesConfigured: schedule.Func(strconv.Atoi, lp.SetPhasesConfigured),
+			keys.EnableThreshold:  schedule.Func(schedule.ParseFloat, schedule.NoError(lp.SetEnableThreshold)),
+			keys.DisableThreshold: schedule.Func(schedule.ParseFloat, schedule.NoError(lp.SetDisableThreshold)),
+			keys.SmartCostLimit:   schedule.Func(schedule.ParseFloatPtr, schedule.NoError(lp.SetSmartCostLimit)),
+			keys.BatteryBoost:     schedule.Func(strconv.ParseBool, lp.SetBatteryBoost),
+		},
+		Save: func(changes []api.ScheduledChange) error {
+			return lp.settings.SetJson(keys.Schedule, changes)
+		},
+		Publish: lp.publish,
+	}
+}
+
+// GetSchedule returns the scheduled setting changes
+func (lp *Loadpoint) GetSchedule() []api.ScheduledChange {
+	return lp.schedule.Get()
+}
+
+// SetSchedule sets the scheduled setting changes
+func (lp *Loadpoint) SetSchedule(changes []api.ScheduledChange) error {
+	return lp.schedule.Set(lp.scheduleOwner(), changes, lp.clock.Now())
+}
+
+// applySchedule applies scheduled changes that became due since the last update
+func (lp *Loadpoint) applySchedule() {
+	lp.schedule.Apply(lp.scheduleOwner(), lp.clock.Now())
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySchedule(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2025, 6, 2, 21, 0, 0, 0, time.Local)) // Monday
+
+	lp := NewLoadpoint(util.NewLogger("foo"), settings.NewDatabaseSettingsAdapter("foo"))
+	lp.clock = clock
+	lp.mode = api.ModePV
+
+	require.NoError(t, lp.SetSchedule([]api.ScheduledChange{
+		{Key: "mode", Value: "minpv", Weekdays: []int{1}, Time: "22:00", Tz: "Local", Active: true},
+		{Key: "limitSoc", Value: "80", At: clock.Now().Add(30 * time.Minute), Active: true},
+	}))
+
+	// unsupported key
+	assert.Error(t, lp.SetSchedule([]api.ScheduledChange{{Key: "foo", At: clock.Now(), Active: true}}))
+
+	// invalid value
+	assert.Error(t, lp.SetSchedule([]api.ScheduledChange{{Key: "mode", Value: "foo", At: clock.Now(), Active: true}}))
+
+	// first update starts schedule
+	lp.applySchedule()
+	assert.Equal(t, api.ModePV, lp.GetMode())
+
+	clock.Add(45 * time.Minute)
+	lp.applySchedule()
+	assert.Equal(t, 80, lp.GetLimitSoc())
+	assert.Equal(t, api.ModePV, lp.GetMode())
+	assert.Len(t, lp.GetSchedule(), 1, "one-off change removed")
+
+	clock.Add(15 * time.Minute)
+	lp.applySchedule()
+	assert.Equal(t, api.ModeMinPV, lp.GetMode())
+
+	// not applied again
+	lp.SetMode(api.ModePV)
+	clock.Add(time.Minute)
+	lp.applySchedule()
+	assert.Equal(t, api.ModePV, lp.GetMode())
+}
+
+func TestApplyScheduleMissed(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2025, 6, 2, 21, 0, 0, 0, time.Local))
+
+	lp := NewLoadpoint(util.NewLogger("foo"), settings.NewDatabaseSettingsAdapter("foo"))
+	lp.clock = clock
+	lp.mode = api.ModePV
+
+	// one-off change falling due while not running
+	lp.schedule.Restore([]api.ScheduledChange{
+		{Key: "mode", Value: "now", At: clock.Now().Add(-time.Hour), Active: true},
+		{Key: "limitSoc", Value: "80", At: clock.Now().Add(time.Hour), Active: true},
+	})
+
+	// skipped on startup
+	lp.applySchedule()
+	assert.Equal(t, api.ModePV, lp.GetMode())
+	assert.Len(t, lp.GetSchedule(), 1, "missed change removed")
+}
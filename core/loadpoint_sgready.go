@@ -0,0 +1,81 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/sgready"
+	"github.com/evcc-io/evcc/tariff"
+)
+
+// sgReadyInputs returns the conditions for selecting the sg-ready state
+func (lp *Loadpoint) sgReadyInputs(sitePower float64) sgready.Inputs {
+	res := sgready.Inputs{
+		Locked: lp.circuit != nil && lp.circuit.Dimmed() || lp.offGridActive() && lp.OffGridMode == api.ModeOff,
+	}
+
+	// normal operation unless locked
+	if lp.GetMode() == api.ModeOff {
+		return res
+	}
+
+	// surplus including the device's own consumption
+	res.Surplus = lp.chargePower - sitePower
+
+	if len(lp.site.GetBatteryMeterRefs()) > 0 {
+		res.BatterySoc = new(lp.site.GetBatterySoc())
+	}
+
+	// grid prices don't apply in off-grid operation
+	if lp.offGridActive() {
+		return res
+	}
+
+	if rate, err := tariff.At(lp.site.GetTariff(api.TariffUsageGrid), lp.clock.Now()); err == nil {
+		res.Price = new(rate.Value)
+	}
+
+	return res
+}
+
+// updateSgReady selects and applies the sg-ready state from pv surplus, battery soc, grid price and grid operator lock
+func (lp *Loadpoint) updateSgReady(sitePower float64) error {
+	dev, ok := api.Cap[api.SgReady](lp.charger)
+	if !ok {
+		return api.ErrNotAvailable
+	}
+
+	state, changed := lp.sgready.Update(lp.sgReadyInputs(sitePower))
+
+	lp.publish(keys.SgReadyState, state)
+	lp.publish(keys.SgReadyBoosts, lp.sgready.Boosts())
+	lp.publish(keys.Dimmed, state == sgready.Lock)
+
+	if changed {
+		lp.publish(keys.SgReadyHistory, lp.sgready.History())
+	}
+
+	current, err := dev.SgReadyState()
+	if err != nil || current == state {
+		return err
+	}
+
+	err = dev.SetSgReadyState(state)
+
+	// devices without dim relay cannot force operation, boost instead
+	if state == sgready.Force && errors.Is(err, api.ErrNotAvailable) {
+		if state = sgready.Boost; current == state {
+			return nil
+		}
+		err = dev.SetSgReadyState(state)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lp.log.INFO.Printf("sgready state: %d", state)
+
+	return nil
+}
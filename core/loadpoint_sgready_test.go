@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/core/sgready"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type sgReadyCharger struct {
+	*api.MockCharger
+	state   int64
+	noForce bool
+}
+
+func (c *sgReadyCharger) SgReadyState() (int64, error) {
+	return c.state, nil
+}
+
+func (c *sgReadyCharger) SetSgReadyState(state int64) error {
+	if c.noForce && state == sgready.Force {
+		return api.ErrNotAvailable
+	}
+	c.state = state
+	return nil
+}
+
+func TestUpdateSgReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := &sgReadyCharger{MockCharger: api.NewMockCharger(ctrl), state: sgready.Normal}
+
+	lp := NewLoadpoint(util.NewLogger("foo"), settings.NewDatabaseSettingsAdapter("foo"))
+	lp.clock = clock.NewMock()
+	lp.site = &Site{tariffs: &tariff.Tariffs{}}
+	lp.charger = charger
+	lp.mode = api.ModePV
+	lp.sgready = sgready.New(lp.clock, sgready.Config{BoostSurplus: 2000})
+
+	// surplus includes own consumption
+	lp.chargePower = 1000
+	require.NoError(t, lp.updateSgReady(-1500))
+	assert.Equal(t, sgready.Boost, charger.state)
+
+	// off mode returns to normal operation after dwell time
+	lp.mode = api.ModeOff
+	lp.clock.(*clock.Mock).Add(sgready.DefaultMinDwell)
+	require.NoError(t, lp.updateSgReady(-1500))
+	assert.Equal(t, sgready.Normal, charger.state)
+	assert.Len(t, lp.sgready.History(), 2)
+}
+
+func TestUpdateSgReadyForceNotAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := &sgReadyCharger{MockCharger: api.NewMockCharger(ctrl), state: sgready.Normal, noForce: true}
+
+	lp := NewLoadpoint(util.NewLogger("foo"), settings.NewDatabaseSettingsAdapter("foo"))
+	lp.clock = clock.NewMock()
+	lp.site = &Site{tariffs: &tariff.Tariffs{}}
+	lp.charger = charger
+	lp.mode = api.ModePV
+	lp.sgready = sgready.New(lp.clock, sgready.Config{ForceSurplus: 2000})
+
+	// force falls back to boost
+	lp.chargePower = 1000
+	require.NoError(t, lp.updateSgReady(-1500))
+	assert.Equal(t, sgready.Boost, charger.state)
+
+	// no error while boosting instead
+	require.NoError(t, lp.updateSgReady(-1500))
+	assert.Equal(t, sgready.Boost, charger.state)
+}
@@ -0,0 +1,168 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/thermal"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/samber/lo"
+)
+
+// thermalHorizon is the planning horizon if no tariff rates are available
+const thermalHorizon = 24 * time.Hour
+
+// configureThermal creates the thermal storage model of heating devices
+func (lp *Loadpoint) configureThermal() error {
+	if !lp.chargerHasFeature(api.Heating) {
+		return errors.New("charger is not a heating device")
+	}
+
+	model, err := thermal.New(lp.Thermal)
+	if err != nil {
+		return err
+	}
+
+	if lp.Thermal.Outside != nil {
+		if lp.thermalOutside, err = lp.Thermal.Outside.FloatGetter(util.WithLogger(context.TODO(), lp.log)); err != nil {
+			return err
+		}
+	}
+
+	lp.thermal = model
+
+	return nil
+}
+
+// thermalForecast returns the outside temperature forecast
+func (lp *Loadpoint) thermalForecast() api.Rates {
+	if lp.Thermal.Forecast == "" {
+		return nil
+	}
+
+	dev, err := config.Tariffs().ByName(lp.Thermal.Forecast)
+	if err != nil {
+		lp.log.ERROR.Printf("thermal: %v", err)
+		return nil
+	}
+
+	return tariff.Rates(dev.Instance())
+}
+
+// outsideTemp returns the current outside temperature
+func (lp *Loadpoint) outsideTemp(forecast api.Rates) (float64, bool) {
+	if lp.thermalOutside != nil {
+		temp, err := lp.thermalOutside()
+		if err == nil {
+			return temp, true
+		}
+		lp.log.ERROR.Printf("thermal: outside temperature: %v", err)
+	}
+
+	if r, err := forecast.At(lp.clock.Now()); err == nil {
+		return r.Value, true
+	}
+
+	if lp.Thermal.Ambient != nil {
+		return *lp.Thermal.Ambient, false
+	}
+
+	return 0, false
+}
+
+// thermalSlots returns the planning slots with grid prices and outside temperatures.
+// Slots with sufficient solar forecast are priced at the feed-in rate.
+func (lp *Loadpoint) thermalSlots(power, outside float64, forecast api.Rates) []thermal.Slot {
+	now := lp.clock.Now()
+
+	grid := tariff.Rates(lp.site.GetTariff(api.TariffUsagePlanner))
+	feedin := tariff.Rates(lp.site.GetTariff(api.TariffUsageFeedIn))
+	solar := tariff.Rates(lp.site.GetTariff(api.TariffUsageSolar))
+
+	end := now.Add(thermalHorizon)
+	if len(grid) > 0 {
+		end = lo.MaxBy(grid, func(a, b api.Rate) bool { return a.End.After(b.End) }).End
+	}
+
+	var res []thermal.Slot
+
+	for start := now.Truncate(tariff.SlotDuration); start.Before(end); start = start.Add(tariff.SlotDuration) {
+		slot := thermal.Slot{
+			Start:   start,
+			End:     start.Add(tariff.SlotDuration),
+			Outside: outside,
+		}
+
+		if r, err := grid.At(start); err == nil {
+			slot.Price = r.Value
+		}
+
+		if r, err := solar.At(start); err == nil && r.Value >= power {
+			slot.Price = 0
+			if r, err := feedin.At(start); err == nil {
+				slot.Price = r.Value
+			}
+		}
+
+		if r, err := forecast.At(start); err == nil {
+			slot.Outside = r.Value
+		}
+
+		res = append(res, slot)
+	}
+
+	// first slot starts now
+	if len(res) > 0 {
+		res[0].Start = now
+	}
+
+	return res
+}
+
+// thermalRequired checks if heating is required for keeping the lower comfort bound.
+// Heating is planned into the cheapest slots by cost of heat before the temperature is predicted to drop below the bound.
+func (lp *Loadpoint) thermalRequired() (required bool) {
+	if lp.thermal == nil {
+		return false
+	}
+
+	var plan []thermal.Slot
+
+	defer func() {
+		lp.publish(keys.ThermalPlan, plan)
+		lp.publish(keys.ThermalRequired, required)
+	}()
+
+	forecast := lp.thermalForecast()
+
+	outside, ok := lp.outsideTemp(forecast)
+	if ok {
+		lp.publish(keys.OutsideTemp, outside)
+	}
+
+	// temperature unknown
+	if !lp.connected() || lp.vehicleSoc == 0 {
+		return false
+	}
+
+	power := lp.EffectiveMaxPower()
+	plan = lp.thermal.Plan(lp.vehicleSoc, power, lp.thermalSlots(power, outside, forecast))
+
+	if len(plan) == 0 || !plan[0].Heat {
+		return false
+	}
+
+	lp.log.DEBUG.Printf("thermal: heating to keep %.1f°C (current %.1f°C, outside %.1f°C)", lp.thermal.MinTemp(), lp.vehicleSoc, outside)
+
+	return true
+}
+
+// thermalLimitReached returns true if the upper comfort bound has been reached
+func (lp *Loadpoint) thermalLimitReached() bool {
+	return lp.thermal != nil && lp.vehicleSoc >= lp.thermal.MaxTemp()
+}
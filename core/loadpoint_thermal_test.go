@@ -0,0 +1,87 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/core/thermal"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestThermalRequired(t *testing.T) {
+	Voltage = 230
+
+	ctrl := gomock.NewController(t)
+
+	clock := clock.NewMock()
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.Local)
+	clock.Set(start)
+
+	// cheap rate from 02:00 to 03:00
+	var rates api.Rates
+	for i := range 6 {
+		price := 0.3
+		if i == 2 {
+			price = 0.1
+		}
+		rates = append(rates, api.Rate{
+			Start: start.Add(time.Duration(i) * time.Hour),
+			End:   start.Add(time.Duration(i+1) * time.Hour),
+			Value: price,
+		})
+	}
+
+	grid := api.NewMockTariff(ctrl)
+	grid.EXPECT().Type().Return(api.TariffTypePriceForecast).AnyTimes()
+	grid.EXPECT().Rates().Return(rates, nil).AnyTimes()
+
+	model, err := thermal.New(thermal.Config{
+		Volume:  300,
+		Loss:    20,
+		Ambient: new(20.0),
+		MinTemp: 45,
+		MaxTemp: 60,
+	})
+	require.NoError(t, err)
+
+	lp := NewLoadpoint(util.NewLogger("foo"), settings.NewDatabaseSettingsAdapter("foo"))
+	lp.clock = clock
+	lp.site = &Site{tariffs: &tariff.Tariffs{Grid: grid}}
+	lp.charger = api.NewMockCharger(ctrl)
+	lp.thermal = model
+	lp.status = api.StatusB
+	lp.phases = 1
+	lp.maxCurrent = 16
+
+	// temperature unknown
+	assert.False(t, lp.thermalRequired())
+
+	// lower bound kept until cheap slot
+	lp.vehicleSoc = 50
+	assert.False(t, lp.thermalRequired())
+
+	// heating deferred to the end of the cheap slot
+	clock.Set(start.Add(2 * time.Hour))
+	lp.vehicleSoc = 47
+	assert.False(t, lp.thermalRequired())
+
+	clock.Set(start.Add(2*time.Hour + 45*time.Minute))
+	assert.True(t, lp.thermalRequired())
+
+	// lower bound reached in expensive slot
+	clock.Set(start.Add(4 * time.Hour))
+	lp.vehicleSoc = 45.2
+	assert.True(t, lp.thermalRequired())
+
+	// upper bound
+	assert.False(t, lp.thermalLimitReached())
+	lp.vehicleSoc = 60
+	assert.True(t, lp.thermalLimitReached())
+}
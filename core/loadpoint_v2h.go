@@ -0,0 +1,104 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/vehicle"
+)
+
+const (
+	// v2hSocMargin keeps discharging above min soc, overshooting must not trigger min soc charging
+	v2hSocMargin = 5
+
+	// v2hSocHysteresis is the additional soc required for resuming discharging
+	v2hSocHysteresis = 2
+)
+
+// v2hAvailable returns true if the connected vehicle can be used as home battery
+func (lp *Loadpoint) v2hAvailable() bool {
+	return lp.GetMode() == api.ModeV2H && lp.connected() && api.HasCap[api.Discharger](lp.charger)
+}
+
+// setDischarge sets the vehicle discharge power
+func (lp *Loadpoint) setDischarge(power float64) error {
+	if power == lp.dischargePower {
+		return nil
+	}
+
+	d, ok := api.Cap[api.Discharger](lp.charger)
+	if !ok {
+		return nil
+	}
+
+	if err := d.Discharge(power); err != nil {
+		return fmt.Errorf("discharge: %w", err)
+	}
+
+	lp.log.DEBUG.Printf("discharge power: %.0fW", power)
+
+	lp.dischargePower = power
+	lp.publish(keys.DischargePower, power)
+
+	return nil
+}
+
+// v2hMinSoc returns the vehicle's min soc for discharging, 0 if not configured
+func (lp *Loadpoint) v2hMinSoc() int {
+	v := lp.GetVehicle()
+	if v == nil {
+		return 0
+	}
+
+	return vehicle.Settings(lp.log, v).GetMinSoc()
+}
+
+// v2hFloorSoc returns the soc discharging stops at. Discharging resumes above floor plus hysteresis.
+func (lp *Loadpoint) v2hFloorSoc(minSoc int) float64 {
+	floor := float64(min(minSoc+v2hSocMargin, 100))
+	if lp.dischargePower == 0 {
+		floor += v2hSocHysteresis
+	}
+	return floor
+}
+
+// dischargeVehicle discharges the vehicle to cover the site's consumption down to the discharge floor above the vehicle's min soc
+func (lp *Loadpoint) dischargeVehicle(sitePower float64) error {
+	// charging is disabled while discharging
+	if err := lp.setLimit(0); err != nil {
+		return err
+	}
+
+	if !api.HasCap[api.Discharger](lp.charger) {
+		if !lp.v2hWarned {
+			lp.log.WARN.Println("v2h: charger does not support discharging")
+			lp.v2hWarned = true
+		}
+		return nil
+	}
+
+	minSoc := lp.v2hMinSoc()
+	if minSoc == 0 {
+		lp.log.WARN.Println("v2h: vehicle min soc required")
+		return lp.setDischarge(0)
+	}
+
+	if soc, floor := lp.GetSoc(), lp.v2hFloorSoc(minSoc); soc <= floor {
+		lp.log.DEBUG.Printf("v2h: vehicle soc %.0f%% at discharge floor %.0f%% (min soc %d%%)", soc, floor, minSoc)
+		return lp.setDischarge(0)
+	}
+
+	// vehicle is treated like the home battery
+	if lp.site != nil {
+		if mode := lp.site.GetBatteryMode(); mode == api.BatteryHold || mode == api.BatteryCharge {
+			lp.log.DEBUG.Printf("v2h: battery mode %s", mode)
+			return lp.setDischarge(0)
+		}
+	}
+
+	// charge power is negative while discharging
+	power := min(max(0, sitePower-lp.GetChargePower()), lp.EffectiveMaxPower())
+
+	return lp.setDischarge(power)
+}
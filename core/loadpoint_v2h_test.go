@@ -0,0 +1,48 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type dischargeCharger struct {
+	*api.MockCharger
+}
+
+func (c *dischargeCharger) Discharge(power float64) error {
+	return nil
+}
+
+func TestV2HAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lp := NewLoadpoint(util.NewLogger("foo"), nil)
+	lp.mode = api.ModeV2H
+	lp.status = api.StatusB
+	lp.charger = api.NewMockCharger(ctrl)
+
+	// charger without discharging
+	assert.False(t, lp.v2hAvailable())
+
+	lp.charger = &dischargeCharger{api.NewMockCharger(ctrl)}
+	assert.True(t, lp.v2hAvailable())
+
+	// vehicle disconnected
+	lp.status = api.StatusA
+	assert.False(t, lp.v2hAvailable())
+}
+
+func TestV2HFloorSoc(t *testing.T) {
+	lp := NewLoadpoint(util.NewLogger("foo"), nil)
+
+	// resume above floor plus hysteresis
+	assert.Equal(t, float64(20+v2hSocMargin+v2hSocHysteresis), lp.v2hFloorSoc(20))
+
+	// keep discharging down to floor above min soc
+	lp.dischargePower = 1000
+	assert.Equal(t, float64(20+v2hSocMargin), lp.v2hFloorSoc(20))
+}
@@ -78,6 +78,7 @@ func (lp *Loadpoint) identifyVehicle() {
 		if vehicle := lp.selectVehicleByID(id); vehicle != nil {
 			lp.stopVehicleDetection()
 			lp.setActiveVehicle(vehicle)
+			lp.confirmVehicle()
 		}
 	}
 }
@@ -131,6 +132,7 @@ func (lp *Loadpoint) setActiveVehicle(v api.Vehicle) {
 	}
 
 	lp.vehicle = v
+	lp.vehicleConfirmed = false
 	lp.vmu.Unlock()
 
 	if from != to {
@@ -208,6 +210,8 @@ func (lp *Loadpoint) unpublishVehicleIdentity() {
 // unpublishVehicle resets published vehicle data
 func (lp *Loadpoint) unpublishVehicle() {
 	lp.vehicleSoc = 0
+	lp.vehicleRange = 0
+	lp.vehicleLimitSoc = 0
 
 	lp.publish(keys.VehicleClimaterActive, nil)
 	lp.publish(keys.VehicleSoc, 0.0)
@@ -289,9 +293,10 @@ func (lp *Loadpoint) identifyVehicleByStatus() {
 		return
 	}
 
-	if vehicle := lp.coordinator.IdentifyVehicleByStatus(); vehicle != nil {
+	if vehicle := lp.coordinator.IdentifyVehicleByStatus(lp.Position); vehicle != nil {
 		lp.stopVehicleDetection()
 		lp.setActiveVehicle(vehicle)
+		lp.confirmVehicle()
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/tariff"
 	"gorm.io/gorm"
@@ -32,6 +33,25 @@ func Persist(ts time.Time, value float64) error {
 	}).Error
 }
 
+// Consumption returns the recorded 15min consumption in Wh between from and to
+func Consumption(from, to time.Time) (api.Rates, error) {
+	var res []meter
+	if err := db.Instance.Where("meter = ? AND ts >= ? AND ts < ?", 1, from, to).Order("ts").Find(&res).Error; err != nil {
+		return nil, err
+	}
+
+	rates := make(api.Rates, 0, len(res))
+	for _, m := range res {
+		rates = append(rates, api.Rate{
+			Start: m.Timestamp,
+			End:   m.Timestamp.Add(tariff.SlotDuration),
+			Value: m.Value,
+		})
+	}
+
+	return rates, nil
+}
+
 // Profile returns a 15min average meter profile in Wh.
 // Profile is sorted by timestamp starting at 00:00. It is guaranteed to contain 96 15min values.
 func Profile(from time.Time) (*[96]float64, error) {
@@ -0,0 +1,121 @@
+package schedule
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Change is an upcoming setting change
+type Change struct {
+	Time  time.Time `json:"time"`
+	Key   string    `json:"key"`
+	Value string    `json:"value"`
+}
+
+// recurring returns true if the change repeats weekly
+func recurring(c api.ScheduledChange) bool {
+	return len(c.Weekdays) > 0
+}
+
+// location returns the change's timezone, defaulting to local time
+func location(c api.ScheduledChange) string {
+	return cmp.Or(c.Tz, "Local")
+}
+
+// Validate checks the change for consistency, supported keys and valid values
+func Validate(c api.ScheduledChange, settings map[string]Setting) error {
+	s, ok := settings[c.Key]
+	if !ok {
+		return fmt.Errorf("unsupported key: %s", c.Key)
+	}
+
+	if err := s.validate(c.Value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", c.Key, err)
+	}
+
+	if !recurring(c) {
+		if c.At.IsZero() {
+			return errors.New("missing time or weekdays")
+		}
+		return nil
+	}
+
+	for _, day := range c.Weekdays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("weekday out of range: %v", day)
+		}
+	}
+
+	if _, err := time.LoadLocation(location(c)); err != nil {
+		return fmt.Errorf("invalid timezone: %v", err)
+	}
+
+	if _, err := time.Parse("15:04", c.Time); err != nil {
+		return fmt.Errorf("invalid time: %v", err)
+	}
+
+	return nil
+}
+
+// Next returns the change's first occurrence after ts or zero time if there is none
+func Next(c api.ScheduledChange, ts time.Time) time.Time {
+	if !recurring(c) {
+		if c.At.After(ts) {
+			return c.At
+		}
+		return time.Time{}
+	}
+
+	res, err := util.NextOccurrence(ts.Add(time.Nanosecond), c.Weekdays, c.Time, location(c))
+	if err != nil {
+		return time.Time{}
+	}
+
+	return res
+}
+
+// Upcoming returns the next active change after ts
+func Upcoming(changes []api.ScheduledChange, ts time.Time) *Change {
+	var res *Change
+
+	for _, c := range changes {
+		if !c.Active {
+			continue
+		}
+
+		if next := Next(c, ts); !next.IsZero() && (res == nil || next.Before(res.Time)) {
+			res = &Change{Time: next, Key: c.Key, Value: c.Value}
+		}
+	}
+
+	return res
+}
+
+// Due returns the active changes occurring in (from, to] in order of occurrence,
+// and the schedule without expired one-off changes
+func Due(changes []api.ScheduledChange, from, to time.Time) ([]Change, []api.ScheduledChange) {
+	var due []Change
+	remaining := make([]api.ScheduledChange, 0, len(changes))
+
+	for _, c := range changes {
+		if next := Next(c, from); c.Active && !next.IsZero() && !next.After(to) {
+			due = append(due, Change{Time: next, Key: c.Key, Value: c.Value})
+		}
+
+		if recurring(c) || c.At.After(to) {
+			remaining = append(remaining, c)
+		}
+	}
+
+	slices.SortStableFunc(due, func(a, b Change) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return due, remaining
+}
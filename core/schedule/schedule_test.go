@@ -0,0 +1,78 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	keys := map[string]Setting{"mode": Func(api.ChargeModeString, NoError(func(api.ChargeMode) {}))}
+
+	assert.NoError(t, Validate(api.ScheduledChange{Key: "mode", Value: "pv", Weekdays: []int{1, 2}, Time: "22:00", Tz: "Europe/Berlin"}, keys))
+	assert.NoError(t, Validate(api.ScheduledChange{Key: "mode", Value: "pv", At: time.Now()}, keys))
+	assert.Error(t, Validate(api.ScheduledChange{Key: "foo", At: time.Now()}, keys))
+	assert.Error(t, Validate(api.ScheduledChange{Key: "mode", Value: "pv"}, keys))
+	assert.Error(t, Validate(api.ScheduledChange{Key: "mode", Value: "pv", Weekdays: []int{7}, Time: "22:00"}, keys))
+	assert.Error(t, Validate(api.ScheduledChange{Key: "mode", Value: "pv", Weekdays: []int{1}, Time: "25:00"}, keys))
+	assert.Error(t, Validate(api.ScheduledChange{Key: "mode", Value: "pv", Weekdays: []int{1}, Time: "22:00", Tz: "Mars/Olympus"}, keys))
+
+	// values validated with the setting's parser
+	assert.NoError(t, Validate(api.ScheduledChange{Key: "mode", Value: "minpv", At: time.Now()}, keys))
+	assert.Error(t, Validate(api.ScheduledChange{Key: "mode", Value: "foo", At: time.Now()}, keys))
+}
+
+func TestDefaultTimezone(t *testing.T) {
+	c := api.ScheduledChange{Key: "mode", Value: "pv", Weekdays: []int{1}, Time: "22:00"}
+	require.NoError(t, Validate(c, map[string]Setting{"mode": Func(api.ChargeModeString, NoError(func(api.ChargeMode) {}))}))
+
+	// validated and executed in local time
+	next := Next(c, time.Now())
+	assert.Equal(t, time.Local, next.Location())
+	assert.Equal(t, 22, next.Hour())
+}
+
+func TestDue(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Monday
+	monday := time.Date(2025, 6, 2, 0, 0, 0, 0, loc)
+
+	changes := []api.ScheduledChange{
+		{Key: "mode", Value: "pv", Weekdays: []int{1, 2, 3, 4, 5}, Time: "06:00", Tz: "Europe/Berlin", Active: true},
+		{Key: "mode", Value: "minpv", Weekdays: []int{1, 2, 3, 4, 5}, Time: "22:00", Tz: "Europe/Berlin", Active: true},
+		{Key: "limitSoc", Value: "100", At: monday.Add(23 * time.Hour), Active: true},
+		{Key: "priority", Value: "1", Weekdays: []int{1}, Time: "21:00", Tz: "Europe/Berlin"}, // inactive
+	}
+
+	up := Upcoming(changes, monday.Add(7*time.Hour))
+	require.NotNil(t, up)
+	assert.Equal(t, Change{Time: monday.Add(22 * time.Hour), Key: "mode", Value: "minpv"}, *up)
+
+	// nothing due
+	due, remaining := Due(changes, monday.Add(7*time.Hour), monday.Add(21*time.Hour+30*time.Minute))
+	assert.Empty(t, due)
+	assert.Len(t, remaining, 4)
+
+	// recurring change due exactly at boundary
+	due, _ = Due(changes, monday.Add(22*time.Hour-time.Minute), monday.Add(22*time.Hour))
+	assert.Equal(t, []Change{{Time: monday.Add(22 * time.Hour), Key: "mode", Value: "minpv"}}, due)
+
+	// not repeated
+	due, _ = Due(changes, monday.Add(22*time.Hour), monday.Add(22*time.Hour+time.Minute))
+	assert.Empty(t, due)
+
+	// one-off change is removed once due
+	due, remaining = Due(changes, monday.Add(22*time.Hour+time.Minute), monday.Add(23*time.Hour))
+	assert.Equal(t, []Change{{Time: monday.Add(23 * time.Hour), Key: "limitSoc", Value: "100"}}, due)
+	assert.Len(t, remaining, 3)
+
+	// ordered by occurrence
+	due, _ = Due(changes, monday.Add(5*time.Hour), monday.Add(23*time.Hour))
+	require.Len(t, due, 3)
+	assert.Equal(t, []string{"pv", "minpv", "100"}, []string{due[0].Value, due[1].Value, due[2].Value})
+}
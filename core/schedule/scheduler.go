@@ -0,0 +1,140 @@
+package schedule
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Owner provides the settings, persistence and publishing of a site's or loadpoint's schedule
+type Owner struct {
+	Log      *util.Logger
+	Settings map[string]Setting
+	Save     func([]api.ScheduledChange) error
+	Publish  func(key string, val any)
+}
+
+// Schedule keeps the scheduled setting changes and applies them when due
+type Schedule struct {
+	mu      sync.Mutex
+	changes []api.ScheduledChange
+	updated time.Time // last time scheduled changes were applied
+}
+
+// Get returns the scheduled setting changes
+func (s *Schedule) Get() []api.ScheduledChange {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.changes)
+}
+
+// Restore sets the persisted setting changes
+func (s *Schedule) Restore(changes []api.ScheduledChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changes = changes
+}
+
+// Publish publishes the scheduled and the upcoming setting change
+func (s *Schedule) Publish(o Owner, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publish(o, now)
+}
+
+func (s *Schedule) publish(o Owner, now time.Time) {
+	o.Publish(keys.Schedule, s.changes)
+	o.Publish(keys.ScheduleNext, Upcoming(s.changes, now))
+}
+
+// Set validates, persists and publishes the scheduled setting changes
+func (s *Schedule) Set(o Owner, changes []api.ScheduledChange, now time.Time) error {
+	for _, c := range changes {
+		if err := Validate(c, o.Settings); err != nil {
+			return err
+		}
+	}
+
+	o.Log.DEBUG.Printf("set schedule: %v", changes)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.set(o, changes, now)
+}
+
+// set sets the scheduled setting changes (no mutex)
+func (s *Schedule) set(o Owner, changes []api.ScheduledChange, now time.Time) error {
+	if err := o.Save(changes); err != nil {
+		return err
+	}
+
+	s.changes = changes
+	s.publish(o, now)
+
+	return nil
+}
+
+// Apply applies scheduled changes that became due since the last update.
+// One-off changes that fell due while not running are skipped.
+func (s *Schedule) Apply(o Owner, now time.Time) {
+	s.mu.Lock()
+	from := s.updated
+	s.updated = now
+
+	if from.IsZero() {
+		s.skipMissed(o, now)
+		s.mu.Unlock()
+		return
+	}
+
+	due, remaining := Due(s.changes, from, now)
+
+	if len(remaining) != len(s.changes) {
+		if err := s.set(o, remaining, now); err != nil {
+			o.Log.ERROR.Println("schedule:", err)
+		}
+	} else if len(due) > 0 {
+		o.Publish(keys.ScheduleNext, Upcoming(s.changes, now))
+	}
+	s.mu.Unlock()
+
+	for _, c := range due {
+		o.Log.INFO.Printf("scheduled change: %s=%s", c.Key, c.Value)
+
+		setting, ok := o.Settings[c.Key]
+		if !ok {
+			o.Log.ERROR.Printf("scheduled change: unsupported key: %s", c.Key)
+			continue
+		}
+
+		if err := setting.apply(c.Value); err != nil {
+			o.Log.ERROR.Printf("scheduled change: %s: %v", c.Key, err)
+		}
+	}
+}
+
+// skipMissed logs and removes the one-off changes that fell due while not running (no mutex)
+func (s *Schedule) skipMissed(o Owner, now time.Time) {
+	expired := func(c api.ScheduledChange) bool {
+		return !recurring(c) && !c.At.After(now)
+	}
+
+	if !slices.ContainsFunc(s.changes, expired) {
+		return
+	}
+
+	for _, c := range s.changes {
+		if expired(c) && c.Active {
+			o.Log.WARN.Printf("scheduled change missed: %s=%s at %s", c.Key, c.Value, c.At.Local().Format(time.DateTime))
+		}
+	}
+
+	if err := s.set(o, slices.DeleteFunc(slices.Clone(s.changes), expired), now); err != nil {
+		o.Log.ERROR.Println("schedule:", err)
+	}
+}
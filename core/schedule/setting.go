@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Setting parses and applies a scheduled setting value
+type Setting struct {
+	validate func(string) error
+	apply    func(string) error
+}
+
+// Func creates a setting from the value's parser and the setting's setter
+func Func[T any](parse func(string) (T, error), set func(T) error) Setting {
+	return Setting{
+		validate: func(val string) error {
+			_, err := parse(val)
+			return err
+		},
+		apply: func(val string) error {
+			v, err := parse(val)
+			if err != nil {
+				return err
+			}
+			return set(v)
+		},
+	}
+}
+
+// NoError adapts setters not returning an error
+func NoError[T any](set func(T)) func(T) error {
+	return func(v T) error {
+		set(v)
+		return nil
+	}
+}
+
+// ParseFloat parses a float value, rejecting NaN and Inf values
+func ParseFloat(val string) (float64, error) {
+	f, err := strconv.ParseFloat(val, 64)
+	if err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		err = fmt.Errorf("invalid float value: %s", val)
+	}
+	return f, err
+}
+
+// ParseFloatPtr parses an optional float value, empty values reset the setting
+func ParseFloatPtr(val string) (*float64, error) {
+	if val == "" {
+		return nil, nil
+	}
+
+	f, err := ParseFloat(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
@@ -0,0 +1,122 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+var _ Settings = (*memorySettings)(nil)
+
+// memorySettings keeps settings in memory only, they are never persisted
+type memorySettings struct {
+	mu   sync.Mutex
+	data map[string]any
+}
+
+func NewMemorySettingsAdapter() Settings {
+	return &memorySettings{data: make(map[string]any)}
+}
+
+func (s *memorySettings) get(key string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	val, ok := s.data[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return val, nil
+}
+
+func (s *memorySettings) set(key string, val any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = val
+}
+
+func (s *memorySettings) SetString(key string, val string) {
+	s.set(key, val)
+}
+
+func (s *memorySettings) SetInt(key string, val int64) {
+	s.set(key, val)
+}
+
+func (s *memorySettings) SetFloat(key string, val float64) {
+	s.set(key, val)
+}
+
+func (s *memorySettings) SetFloatPtr(key string, val *float64) {
+	if val == nil {
+		s.set(key, "")
+	} else {
+		s.set(key, *val)
+	}
+}
+
+func (s *memorySettings) SetTime(key string, val time.Time) {
+	s.set(key, val)
+}
+
+func (s *memorySettings) SetBool(key string, val bool) {
+	s.set(key, val)
+}
+
+func (s *memorySettings) SetJson(key string, val any) error {
+	b, err := json.Marshal(val)
+	if err == nil {
+		s.set(key, string(b))
+	}
+	return err
+}
+
+func (s *memorySettings) String(key string) (string, error) {
+	val, err := s.get(key)
+	if err != nil {
+		return "", err
+	}
+	return cast.ToStringE(val)
+}
+
+func (s *memorySettings) Int(key string) (int64, error) {
+	val, err := s.get(key)
+	if err != nil {
+		return 0, err
+	}
+	return cast.ToInt64E(val)
+}
+
+func (s *memorySettings) Float(key string) (float64, error) {
+	val, err := s.get(key)
+	if err != nil {
+		return 0, err
+	}
+	return cast.ToFloat64E(val)
+}
+
+func (s *memorySettings) Time(key string) (time.Time, error) {
+	val, err := s.get(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cast.ToTimeE(val)
+}
+
+func (s *memorySettings) Bool(key string) (bool, error) {
+	val, err := s.get(key)
+	if err != nil {
+		return false, err
+	}
+	return cast.ToBoolE(val)
+}
+
+func (s *memorySettings) Json(key string, res any) error {
+	str, err := s.String(key)
+	if str == "" || err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(str), &res)
+}
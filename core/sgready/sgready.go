@@ -0,0 +1,166 @@
+package sgready
+
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// SG-Ready operating states
+const (
+	_      int64 = iota
+	Lock         // 1: operation locked by grid operator or expensive tariff
+	Normal       // 2: normal operation
+	Boost        // 3: increased operation recommended
+	Force        // 4: operation forced on
+)
+
+const (
+	// DefaultMinDwell is the default minimum time in a state before switching
+	DefaultMinDwell = 10 * time.Minute
+
+	maxHistory = 100
+)
+
+// Config defines the thresholds for selecting SG-Ready states
+type Config struct {
+	BoostSurplus   float64       `json:"boostSurplus"`   // pv surplus in W for state 3
+	ForceSurplus   float64       `json:"forceSurplus"`   // pv surplus in W for state 4
+	MinBatterySoc  float64       `json:"minBatterySoc"`  // battery soc in % required for using pv surplus
+	CheapPrice     *float64      `json:"cheapPrice"`     // grid price at or below which state 3 is selected
+	ExpensivePrice *float64      `json:"expensivePrice"` // grid price at or above which state 1 is selected
+	MinDwell       time.Duration `json:"minDwell"`       // minimum time in a state before switching
+	MaxBoosts      int           `json:"maxBoosts"`      // boost periods (states 3 and 4) per day, unlimited if 0
+}
+
+// Configured returns true if SG-Ready state scheduling is configured
+func (c Config) Configured() bool {
+	return c.BoostSurplus > 0 || c.ForceSurplus > 0 || c.CheapPrice != nil || c.ExpensivePrice != nil
+}
+
+// Inputs are the conditions the SG-Ready state is selected from
+type Inputs struct {
+	Surplus    float64  // pv surplus in W
+	BatterySoc *float64 // battery soc in %
+	Price      *float64 // current grid price
+	Locked     bool     // grid operator lock signal
+}
+
+// Change is a recorded SG-Ready state change
+type Change struct {
+	Time   time.Time `json:"time"`
+	From   int64     `json:"from"`
+	To     int64     `json:"to"`
+	Reason string    `json:"reason"`
+}
+
+// Controller selects SG-Ready states enforcing minimum dwell times and a daily number of boost periods
+type Controller struct {
+	clock   clock.Clock
+	config  Config
+	state   int64
+	since   time.Time
+	day     time.Time
+	boosts  int
+	history []Change
+}
+
+// New creates an SG-Ready controller starting in normal operation
+func New(clock clock.Clock, config Config) *Controller {
+	if config.MinDwell <= 0 {
+		config.MinDwell = DefaultMinDwell
+	}
+
+	return &Controller{
+		clock:  clock,
+		config: config,
+		state:  Normal,
+	}
+}
+
+// State returns the current state
+func (c *Controller) State() int64 {
+	return c.state
+}
+
+// History returns the recorded state changes
+func (c *Controller) History() []Change {
+	return c.history
+}
+
+// Boosts returns the number of boost periods started today
+func (c *Controller) Boosts() int {
+	c.resetDay()
+	return c.boosts
+}
+
+// desired returns the state for the given inputs
+func (c *Controller) desired(in Inputs) (int64, string) {
+	if in.Locked {
+		return Lock, "grid operator lock"
+	}
+
+	if c.config.ExpensivePrice != nil && in.Price != nil && *in.Price >= *c.config.ExpensivePrice {
+		return Lock, "expensive price"
+	}
+
+	battery := c.config.MinBatterySoc == 0 || in.BatterySoc == nil || *in.BatterySoc >= c.config.MinBatterySoc
+
+	switch {
+	case battery && c.config.ForceSurplus > 0 && in.Surplus >= c.config.ForceSurplus:
+		return Force, "pv surplus"
+	case battery && c.config.BoostSurplus > 0 && in.Surplus >= c.config.BoostSurplus:
+		return Boost, "pv surplus"
+	case c.config.CheapPrice != nil && in.Price != nil && *in.Price <= *c.config.CheapPrice:
+		return Boost, "cheap price"
+	}
+
+	return Normal, "normal"
+}
+
+// resetDay resets the boost count at the start of a new day
+func (c *Controller) resetDay() {
+	now := c.clock.Now()
+	if day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()); !day.Equal(c.day) {
+		c.day = day
+		c.boosts = 0
+	}
+}
+
+// Update selects the state for the given inputs and returns true if the state has changed.
+// Lock signals are applied immediately, all other changes are delayed until the minimum dwell time has elapsed.
+func (c *Controller) Update(in Inputs) (int64, bool) {
+	c.resetDay()
+
+	state, reason := c.desired(in)
+	if state == c.state {
+		return c.state, false
+	}
+
+	now := c.clock.Now()
+
+	if !in.Locked && !c.since.IsZero() && now.Sub(c.since) < c.config.MinDwell {
+		return c.state, false
+	}
+
+	boost := state >= Boost && c.state < Boost
+	if boost && c.config.MaxBoosts > 0 && c.boosts >= c.config.MaxBoosts {
+		if state, reason = Normal, "boost limit"; state == c.state {
+			return c.state, false
+		}
+		boost = false
+	}
+
+	if boost {
+		c.boosts++
+	}
+
+	c.history = append(c.history, Change{Time: now, From: c.state, To: state, Reason: reason})
+	if len(c.history) > maxHistory {
+		c.history = c.history[len(c.history)-maxHistory:]
+	}
+
+	c.state, c.since = state, now
+
+	return state, true
+}
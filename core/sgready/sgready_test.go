@@ -0,0 +1,89 @@
+package sgready
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDesired(t *testing.T) {
+	c := New(clock.NewMock(), Config{
+		BoostSurplus:   2000,
+		ForceSurplus:   5000,
+		MinBatterySoc:  50,
+		CheapPrice:     new(0.1),
+		ExpensivePrice: new(0.4),
+	})
+
+	for _, tc := range []struct {
+		in    Inputs
+		state int64
+	}{
+		{Inputs{}, Normal},
+		{Inputs{Surplus: 2000}, Boost},
+		{Inputs{Surplus: 6000}, Force},
+		{Inputs{Surplus: 6000, BatterySoc: new(20.0)}, Normal},
+		{Inputs{Surplus: 6000, BatterySoc: new(60.0)}, Force},
+		{Inputs{Price: new(0.05)}, Boost},
+		{Inputs{Price: new(0.5)}, Lock},
+		{Inputs{Surplus: 6000, Locked: true}, Lock},
+	} {
+		state, _ := c.desired(tc.in)
+		assert.Equal(t, tc.state, state, "%+v", tc.in)
+	}
+}
+
+func TestDwell(t *testing.T) {
+	clock := clock.NewMock()
+	c := New(clock, Config{BoostSurplus: 2000})
+
+	state, changed := c.Update(Inputs{Surplus: 3000})
+	assert.True(t, changed)
+	assert.Equal(t, Boost, state)
+
+	// dwell time not elapsed
+	clock.Add(5 * time.Minute)
+	state, changed = c.Update(Inputs{})
+	assert.False(t, changed)
+	assert.Equal(t, Boost, state)
+
+	// lock applied immediately
+	state, changed = c.Update(Inputs{Locked: true})
+	assert.True(t, changed)
+	assert.Equal(t, Lock, state)
+
+	clock.Add(DefaultMinDwell)
+	state, changed = c.Update(Inputs{})
+	assert.True(t, changed)
+	assert.Equal(t, Normal, state)
+
+	assert.Len(t, c.History(), 3)
+	assert.Equal(t, Change{Time: clock.Now(), From: Lock, To: Normal, Reason: "normal"}, c.History()[2])
+}
+
+func TestMaxBoosts(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2025, 1, 6, 8, 0, 0, 0, time.Local))
+	c := New(clock, Config{BoostSurplus: 2000, MaxBoosts: 1})
+
+	state, _ := c.Update(Inputs{Surplus: 3000})
+	assert.Equal(t, Boost, state)
+
+	clock.Add(time.Hour)
+	state, _ = c.Update(Inputs{})
+	assert.Equal(t, Normal, state)
+
+	// boost limit reached
+	clock.Add(time.Hour)
+	state, changed := c.Update(Inputs{Surplus: 3000})
+	assert.False(t, changed)
+	assert.Equal(t, Normal, state)
+	assert.Equal(t, 1, c.Boosts())
+
+	// next day
+	clock.Add(24 * time.Hour)
+	state, _ = c.Update(Inputs{Surplus: 3000})
+	assert.Equal(t, Boost, state)
+}
@@ -0,0 +1,483 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	evcharger "github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/prioritizer"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/messenger"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/jinzhu/now"
+)
+
+// SimulationSession is a recorded charging session
+type SimulationSession struct {
+	Loadpoint string
+	Arrival   time.Time
+	Departure time.Time
+	Energy    float64 // kWh
+}
+
+// SimulationVariant is a charging strategy applied to all loadpoints
+type SimulationVariant struct {
+	Name             string           `yaml:"name"`
+	Mode             api.ChargeMode   `yaml:"mode"`
+	SmartCostLimit   *float64         `yaml:"smartCostLimit"`
+	EnableThreshold  float64          `yaml:"enableThreshold"`
+	DisableThreshold float64          `yaml:"disableThreshold"`
+	Priorities       map[string]int   `yaml:"priorities"`   // loadpoint priorities by title
+	Plan             bool             `yaml:"plan"`         // plan session energy until departure
+	PlanStrategy     api.PlanStrategy `yaml:"planStrategy"` // continuous planning and precondition duration
+}
+
+// SimulationResult is the outcome of simulating a variant
+type SimulationResult struct {
+	Variant         string
+	GridImport      float64 // kWh
+	GridExport      float64 // kWh
+	Pv              float64 // kWh
+	Charged         float64 // kWh
+	Cost            float64 // currency
+	Co2             float64 // kg
+	SelfConsumption float64 // share of pv energy consumed on site
+	PlanFulfillment float64 // share of session energy charged until departure
+}
+
+// Simulation replays recorded household consumption and charging sessions through a site
+// with simulated meters, chargers and vehicles. Recorded data is shifted by offset into the
+// period covered by the tariff and solar forecasts.
+type Simulation struct {
+	log       *util.Logger
+	tariffs   *tariff.Tariffs
+	household api.Rates    // recorded consumption in Wh per 15min slot
+	profile   *[96]float64 // typical consumption in Wh per 15min slot for missing records
+	sessions  []SimulationSession
+	offset    time.Duration
+
+	Capacity        float64       // vehicle capacity in kWh
+	Phases          int           // charger phases
+	BatteryCapacity float64       // home battery capacity in kWh, no battery if zero
+	BatteryPower    float64       // home battery charge and discharge power in W
+	Step            time.Duration // control loop interval
+}
+
+// NewSimulation creates a simulation from recorded consumption and sessions. The profile is optional.
+func NewSimulation(log *util.Logger, tariffs *tariff.Tariffs, household api.Rates, profile *[96]float64, sessions []SimulationSession, offset time.Duration) *Simulation {
+	return &Simulation{
+		log:       log,
+		tariffs:   tariffs,
+		household: household,
+		profile:   profile,
+		sessions:  sessions,
+		offset:    offset,
+		Capacity:  60,
+		Phases:    3,
+		Step:      time.Minute,
+	}
+}
+
+// SimulationSessions converts finished sessions from session history
+func SimulationSessions(sessions session.Sessions) []SimulationSession {
+	var res []SimulationSession
+
+	for _, s := range sessions {
+		if s.Finished.IsZero() || s.ChargedEnergy <= 0 {
+			continue
+		}
+
+		res = append(res, SimulationSession{
+			Loadpoint: s.Loadpoint,
+			Arrival:   s.Created,
+			Departure: s.Finished,
+			Energy:    s.ChargedEnergy,
+		})
+	}
+
+	return res
+}
+
+// simulatedMeter is a meter reading simulated power
+type simulatedMeter func() float64
+
+func (m simulatedMeter) CurrentPower() (float64, error) {
+	return m(), nil
+}
+
+// simulatedBattery is a home battery balancing the site's demand within its power limits
+type simulatedBattery struct {
+	mu       sync.Mutex
+	demand   func() float64 // W
+	capacity float64        // kWh
+	power    float64        // W
+	soc      float64        // %
+	mode     api.BatteryMode
+}
+
+// balance returns the battery power for the given demand, positive for discharging
+func (b *simulatedBattery) balance(demand float64) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.mode {
+	case api.BatteryHold:
+		demand = min(demand, 0)
+	case api.BatteryCharge:
+		demand = -b.power
+	}
+
+	res := min(max(demand, -b.power), b.power)
+	if res > 0 && b.soc <= 0 || res < 0 && b.soc >= 100 {
+		return 0
+	}
+
+	return res
+}
+
+// update updates the soc from the battery power over the given duration
+func (b *simulatedBattery) update(power float64, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.soc = min(max(b.soc-100*power*d.Hours()/1e3/b.capacity, 0), 100)
+}
+
+func (b *simulatedBattery) CurrentPower() (float64, error) {
+	return b.balance(b.demand()), nil
+}
+
+func (b *simulatedBattery) Soc() (float64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.soc, nil
+}
+
+func (b *simulatedBattery) Capacity() float64 {
+	return b.capacity
+}
+
+func (b *simulatedBattery) SetBatteryMode(mode api.BatteryMode) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mode = mode
+	return nil
+}
+
+// simulatedSite provides the physical site state for the simulated meters
+type simulatedSite struct {
+	mu            sync.Mutex
+	household, pv float64 // W
+	chargers      []*evcharger.Simulator
+	battery       *simulatedBattery
+}
+
+func (s *simulatedSite) set(household, pv float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.household, s.pv = household, pv
+}
+
+func (s *simulatedSite) chargePower() float64 {
+	var res float64
+	for _, wb := range s.chargers {
+		f, _ := wb.CurrentPower()
+		res += f
+	}
+	return res
+}
+
+// demand returns the site's power demand before battery, positive for import
+func (s *simulatedSite) demand() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.household + s.chargePower() - s.pv
+}
+
+func (s *simulatedSite) pvPower() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pv
+}
+
+func (s *simulatedSite) gridPower() float64 {
+	demand := s.demand()
+	if s.battery != nil {
+		demand -= s.battery.balance(demand)
+	}
+	return demand
+}
+
+// simulatedLoadpoint is a loadpoint with simulated charger replaying recorded sessions
+type simulatedLoadpoint struct {
+	*Loadpoint
+	charger  *evcharger.Simulator
+	sessions []SimulationSession // pending sessions in simulated time
+	session  *SimulationSession  // connected session
+}
+
+// Run simulates the variant for the given period
+func (s *Simulation) Run(start time.Time, duration time.Duration, variant SimulationVariant) (SimulationResult, error) {
+	res := SimulationResult{Variant: variant.Name}
+
+	if Voltage == 0 {
+		Voltage = 230 // V
+	}
+
+	clock := clock.NewMock()
+	clock.Set(start)
+
+	world := new(simulatedSite)
+
+	site := NewSite()
+	site.name = "simulation"
+	site.log = s.log
+	site.clock = clock
+	site.fcstEnergy = &meterEnergy{clock: clock}
+	site.householdEnergy = &meterEnergy{clock: clock}
+	site.tariffs = s.tariffs
+	site.prioritizer = prioritizer.New(s.log)
+	site.stats = NewStats()
+
+	site.gridMeter = simulatedMeter(world.gridPower)
+	site.pvMeters = []config.Device[api.Meter]{config.NewStaticDevice(config.Named{Name: "pv"}, api.Meter(simulatedMeter(world.pvPower)))}
+	site.pvEnergy["pv"] = &meterEnergy{clock: clock}
+
+	if s.BatteryCapacity > 0 {
+		world.battery = &simulatedBattery{
+			demand:   world.demand,
+			capacity: s.BatteryCapacity,
+			power:    s.BatteryPower,
+			soc:      50,
+			mode:     api.BatteryNormal,
+		}
+
+		site.batteryMeters = []config.Device[api.Meter]{config.NewStaticDevice(config.Named{Name: "battery"}, api.Meter(world.battery))}
+	}
+
+	pushChan := make(chan messenger.Event)
+	defer close(pushChan)
+
+	go func() {
+		for range pushChan {
+		}
+	}()
+
+	lpChan := make(chan *Loadpoint, 1)
+
+	var (
+		lps      []*simulatedLoadpoint
+		updaters []updater
+	)
+
+	for i, title := range s.loadpoints() {
+		lp, err := s.loadpoint(i, clock, title, variant)
+		if err != nil {
+			return res, err
+		}
+
+		lps = append(lps, lp)
+		updaters = append(updaters, lp.Loadpoint)
+		world.chargers = append(world.chargers, lp.charger)
+		site.loadpoints = append(site.loadpoints, lp.Loadpoint)
+	}
+
+	for _, lp := range lps {
+		lp.Prepare(site, nil, pushChan, lpChan)
+
+		if prio, ok := variant.Priorities[lp.GetTitle()]; ok {
+			lp.SetPriority(prio)
+		}
+
+		if err := lp.SetPlanStrategy(variant.PlanStrategy); err != nil {
+			return res, fmt.Errorf("variant %s: %w", variant.Name, err)
+		}
+	}
+
+	gridRates := tariff.Rates(s.tariffs.Get(api.TariffUsageGrid))
+	feedinRates := tariff.Rates(s.tariffs.Get(api.TariffUsageFeedIn))
+	co2Rates := tariff.Rates(s.tariffs.Get(api.TariffUsageCo2))
+	solarRates := tariff.Rates(s.tariffs.Get(api.TariffUsageSolar))
+
+	rateAt := func(rates api.Rates, ts time.Time) float64 {
+		r, _ := rates.At(ts)
+		return r.Value
+	}
+
+	var fulfillment []float64
+	end := start.Add(duration)
+
+	for ts := start; ts.Before(end); ts = clock.Now() {
+		for _, lp := range lps {
+			if f, ok := lp.replay(ts, s.Capacity, variant.Plan); ok {
+				fulfillment = append(fulfillment, f)
+			}
+		}
+
+		world.set(s.householdPower(ts), max(rateAt(solarRates, ts), 0))
+
+		// drain loadpoint update requests, all loadpoints are updated each step
+		select {
+		case <-lpChan:
+		default:
+		}
+
+		site.update(updaters...)
+
+		demand := world.demand()
+		charge := world.chargePower()
+
+		var battery float64
+		if world.battery != nil {
+			battery = world.battery.balance(demand)
+			world.battery.update(battery, s.Step)
+		}
+
+		grid := demand - battery
+		hours := s.Step.Hours()
+
+		imp := max(grid, 0) * hours / 1e3
+		exp := max(-grid, 0) * hours / 1e3
+
+		res.GridImport += imp
+		res.GridExport += exp
+		res.Pv += world.pvPower() * hours / 1e3
+		res.Charged += max(charge, 0) * hours / 1e3
+		res.Cost += imp*rateAt(gridRates, ts) - exp*rateAt(feedinRates, ts)
+		res.Co2 += imp * rateAt(co2Rates, ts) / 1e3
+
+		clock.Add(s.Step)
+	}
+
+	if res.Pv > 0 {
+		res.SelfConsumption = 1 - res.GridExport/res.Pv
+	}
+
+	if len(fulfillment) > 0 {
+		var sum float64
+		for _, f := range fulfillment {
+			sum += f
+		}
+		res.PlanFulfillment = sum / float64(len(fulfillment))
+	}
+
+	return res, nil
+}
+
+// loadpoints returns the titles of all loadpoints with recorded sessions
+func (s *Simulation) loadpoints() []string {
+	var res []string
+	for _, ss := range s.sessions {
+		if !slices.Contains(res, ss.Loadpoint) {
+			res = append(res, ss.Loadpoint)
+		}
+	}
+	return res
+}
+
+// householdPower returns the recorded household power at the simulated time
+func (s *Simulation) householdPower(ts time.Time) float64 {
+	slots := float64(time.Hour / tariff.SlotDuration)
+
+	if r, err := s.household.At(ts.Add(-s.offset)); err == nil {
+		return r.Value * slots
+	}
+
+	if s.profile == nil {
+		return 0
+	}
+
+	slot := int(ts.Local().Sub(now.With(ts.Local()).BeginningOfDay()) / tariff.SlotDuration)
+	return s.profile[slot%96] * slots
+}
+
+// loadpoint creates a loadpoint with simulated charger applying the variant's strategy
+func (s *Simulation) loadpoint(i int, clock clock.Clock, title string, variant SimulationVariant) (*simulatedLoadpoint, error) {
+	log := util.NewLogger(fmt.Sprintf("sim-lp-%d", i+1))
+
+	mode := cmp.Or(variant.Mode, api.ModePV)
+	if _, err := api.ChargeModeString(string(mode)); err != nil {
+		return nil, fmt.Errorf("variant %s: %w", variant.Name, err)
+	}
+
+	wb := evcharger.NewSimulator(clock, api.StatusA, s.Phases, s.Capacity, 0)
+
+	lp := NewLoadpoint(log, settings.NewMemorySettingsAdapter())
+	lp.clock = clock
+	lp.title = title
+	lp.charger = wb
+	lp.mode = mode
+	lp.DefaultMode = mode
+	lp.smartCostLimit = variant.SmartCostLimit
+	lp.Enable.Threshold = variant.EnableThreshold
+	lp.Disable.Threshold = variant.DisableThreshold
+	lp.phases = s.Phases
+	lp.phasesConfigured = s.Phases
+	lp.planner = planner.New(log, s.tariffs.Get(api.TariffUsagePlanner), planner.WithClock(clock))
+
+	lp.configureChargerType(wb)
+
+	var sessions []SimulationSession
+	for _, ss := range s.sessions {
+		if ss.Loadpoint == title {
+			ss.Arrival = ss.Arrival.Add(s.offset)
+			ss.Departure = ss.Departure.Add(s.offset)
+			sessions = append(sessions, ss)
+		}
+	}
+
+	slices.SortFunc(sessions, func(a, b SimulationSession) int {
+		return a.Arrival.Compare(b.Arrival)
+	})
+
+	return &simulatedLoadpoint{
+		Loadpoint: lp,
+		charger:   wb,
+		sessions:  sessions,
+	}, nil
+}
+
+// replay connects and disconnects the vehicle according to the recorded sessions.
+// On departure, it returns the share of session energy charged.
+func (lp *simulatedLoadpoint) replay(ts time.Time, capacity float64, plan bool) (float64, bool) {
+	if ss := lp.session; ss != nil {
+		if ts.Before(ss.Departure) {
+			return 0, false
+		}
+
+		lp.session = nil
+
+		energy, _ := lp.charger.ChargedEnergy()
+		lp.charger.SetStatus(api.StatusA, 0)
+
+		return min(energy/ss.Energy, 1), true
+	}
+
+	if len(lp.sessions) == 0 || ts.Before(lp.sessions[0].Arrival) {
+		return 0, false
+	}
+
+	ss := lp.sessions[0]
+	lp.sessions = lp.sessions[1:]
+	lp.session = &ss
+
+	lp.charger.SetStatus(api.StatusB, max(0, 100-100*ss.Energy/capacity))
+	lp.SetLimitEnergy(ss.Energy)
+
+	if plan {
+		if err := lp.SetPlanEnergy(ss.Departure, ss.Energy); err != nil {
+			lp.log.ERROR.Println("plan:", err)
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
+	"github.com/jinzhu/now"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSimulationSessions(t *testing.T) {
+	day := now.BeginningOfDay()
+
+	res := SimulationSessions(session.Sessions{
+		{Loadpoint: "garage", Created: day.Add(17 * time.Hour), Finished: day.Add(31 * time.Hour), ChargedEnergy: 10},
+		{Loadpoint: "garage", Created: day.Add(20 * time.Hour)}, // unfinished
+	})
+
+	require.Len(t, res, 1)
+	assert.Equal(t, SimulationSession{
+		Loadpoint: "garage",
+		Arrival:   day.Add(17 * time.Hour),
+		Departure: day.Add(31 * time.Hour),
+		Energy:    10,
+	}, res[0])
+}
+
+func TestSimulation(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	ctrl := gomock.NewController(t)
+
+	start := now.BeginningOfDay().Add(17 * time.Hour)
+
+	// expensive evening, cheap night
+	var rates, solar api.Rates
+	for ts := start; ts.Before(start.Add(24 * time.Hour)); ts = ts.Add(tariff.SlotDuration) {
+		price := 0.4
+		if h := ts.Hour(); h < 6 {
+			price = 0.1
+		}
+		rates = append(rates, api.Rate{Start: ts, End: ts.Add(tariff.SlotDuration), Value: price})
+		solar = append(solar, api.Rate{Start: ts, End: ts.Add(tariff.SlotDuration)})
+	}
+
+	grid := api.NewMockTariff(ctrl)
+	grid.EXPECT().Type().Return(api.TariffTypePriceForecast).AnyTimes()
+	grid.EXPECT().Rates().Return(rates, nil).AnyTimes()
+
+	// recorded yesterday, replayed today
+	recorded := start.AddDate(0, 0, -1)
+	household := api.Rates{{Start: recorded, End: recorded.Add(tariff.SlotDuration), Value: 250}}
+
+	sim := NewSimulation(util.NewLogger("foo"), &tariff.Tariffs{Grid: grid}, household, nil, []SimulationSession{
+		{Loadpoint: "garage", Arrival: recorded.Add(time.Hour), Departure: recorded.Add(14 * time.Hour), Energy: 20},
+		{Loadpoint: "carport", Arrival: recorded.Add(time.Hour), Departure: recorded.Add(14 * time.Hour), Energy: 5},
+	}, 24*time.Hour)
+
+	run := func(variant SimulationVariant) SimulationResult {
+		res, err := sim.Run(start, 15*time.Hour, variant)
+		require.NoError(t, err)
+		return res
+	}
+
+	fast := run(SimulationVariant{Name: "now", Mode: api.ModeNow})
+	assert.InDelta(t, 25, fast.Charged, 0.5)
+	assert.InDelta(t, 1, fast.PlanFulfillment, 0.01)
+	assert.InDelta(t, 25+0.25, fast.GridImport, 0.5) // recorded household slot is replayed
+	assert.InDelta(t, 0.4*fast.GridImport, fast.Cost, 0.5)
+
+	// no pv available
+	pv := run(SimulationVariant{Name: "pv", Mode: api.ModePV})
+	assert.Zero(t, pv.Charged)
+	assert.Zero(t, pv.PlanFulfillment)
+
+	plan := run(SimulationVariant{Name: "plan", Mode: api.ModePV, Plan: true})
+	assert.InDelta(t, 1, plan.PlanFulfillment, 0.01)
+	assert.Less(t, plan.Cost, fast.Cost/2)
+
+	// late plan start
+	late := run(SimulationVariant{Name: "late", Mode: api.ModePV, Plan: true, PlanStrategy: api.PlanStrategy{Precondition: time.Hour}})
+	assert.InDelta(t, 1, late.PlanFulfillment, 0.01)
+
+	// home battery covers household consumption
+	sim.BatteryCapacity = 10
+	sim.BatteryPower = 5000
+
+	battery := run(SimulationVariant{Name: "pv", Mode: api.ModePV})
+	assert.Zero(t, battery.GridImport)
+}
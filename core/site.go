@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"strings"
 	"sync"
@@ -15,6 +16,8 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/cmd/shutdown"
+	"github.com/evcc-io/evcc/core/arbitrage"
+	"github.com/evcc-io/evcc/core/batterystats"
 	"github.com/evcc-io/evcc/core/circuit"
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/keys"
@@ -22,12 +25,14 @@ import (
 	"github.com/evcc-io/evcc/core/metrics"
 	"github.com/evcc-io/evcc/core/planner"
 	"github.com/evcc-io/evcc/core/prioritizer"
+	"github.com/evcc-io/evcc/core/schedule"
 	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/core/types"
 	"github.com/evcc-io/evcc/core/vehicle"
 	"github.com/evcc-io/evcc/messenger"
+	"github.com/evcc-io/evcc/plugin"
 	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/tariff"
@@ -53,17 +58,31 @@ var _ site.API = (*Site)(nil)
 
 // Site is the main configuration container. A site can host multiple loadpoints.
 type Site struct {
-	valueChan    chan<- util.Param // client push messages
+	valueChan    chan<- util.Param      // client push messages
+	pushChan     chan<- messenger.Event // notifications
 	lpUpdateChan chan *Loadpoint
 
 	sync.RWMutex
-	log *util.Logger
+	log   *util.Logger
+	clock clock.Clock
+	name  string // name of additional site, empty for main site
 
 	// configuration
 	Title         string       `mapstructure:"title"`         // UI title
 	Voltage       float64      `mapstructure:"voltage"`       // Operating voltage. 230V for Germany.
 	ResidualPower float64      `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
 	Meters        MetersConfig `mapstructure:"meters"`        // Meter references
+	CircuitRef    string       `mapstructure:"circuit"`       // Root circuit reference
+	PhaseMetering bool         `mapstructure:"phaseMetering"` // Grid import and export are metered per phase
+	FeedInLimit   *float64     `mapstructure:"feedInLimit"`   // Max grid export in W, requires pv meters with active power limit
+
+	BatteryGridChargePower  float64 `mapstructure:"batteryGridChargePower"`  // Grid charging power in W, requires batteries with power control
+	OptimizerBatteryControl bool    `mapstructure:"optimizerBatteryControl"` // Apply optimizer battery power targets, requires batteries with power control
+
+	Arbitrage        *arbitrage.Config `mapstructure:"arbitrage"`        // Battery arbitrage on grid and feed-in rates
+	BatteryCycleCost float64           `mapstructure:"batteryCycleCost"` // Battery wear cost per cycled kWh
+
+	OffGrid *plugin.Config `mapstructure:"offGrid"` // Off-grid (backup) operation signal, alternative to meter capabilities
 
 	// meters
 	circuit       api.Circuit                // Circuit
@@ -92,14 +111,37 @@ type Site struct {
 	householdSlotStart time.Time
 
 	// cached state
-	gridPower                float64            // Grid power
-	pvPower                  float64            // PV power
-	excessDCPower            float64            // PV excess DC charge power (hybrid only)
-	auxPower                 float64            // Aux power
-	battery                  types.BatteryState // Battery cached and published state
-	batteryMode              api.BatteryMode    // Battery mode (runtime only, not persisted)
-	batteryModeExternal      api.BatteryMode    // Battery mode (external, runtime only, not persisted)
-	batteryModeExternalTimer time.Time          // Battery mode timer for external control
+	gridPower                float64                        // Grid power
+	gridPowers               []float64                      // Grid phase powers
+	gridCurrents             []float64                      // Grid phase currents (signed)
+	pvPower                  float64                        // PV power
+	pvPowers                 []float64                      // PV meter powers
+	excessDCPower            float64                        // PV excess DC charge power (hybrid only)
+	auxPower                 float64                        // Aux power
+	battery                  types.BatteryState             // Battery cached and published state
+	batteryMode              api.BatteryMode                // Battery mode (runtime only, not persisted)
+	batteryModeExternal      api.BatteryMode                // Battery mode (external, runtime only, not persisted)
+	batteryModeExternalTimer time.Time                      // Battery mode timer for external control
+	batterySetpoint          *float64                       // Battery power setpoint, nil if not controlled
+	batteryStats             map[string]*batterystats.Stats // Battery cycle accounting by meter name
+	batteryTargets           []batteryTarget                // Optimizer battery power targets
+	homePower                float64                        // Home power
+	offGrid                  bool                           // Off-grid operation
+	offGridG                 func() (bool, error)           // Off-grid operation signal
+
+	// battery arbitrage
+	arbitrageAction  arbitrage.Action // active arbitrage action
+	arbitrageProfit  float64          // achieved arbitrage profit
+	arbitrageUpdated time.Time        // last arbitrage profit accounting
+
+	// scheduled changes
+	schedule schedule.Schedule // scheduled setting changes
+
+	// feed-in limit control
+	pvLimit         float64   // active pv power limit in %, 100 if not limited
+	pvLimitApplied  bool      // pv limit has been applied since startup
+	feedInProbe     time.Time // start of last probe for loadpoints waiting for surplus
+	feedInSessionID uint      // smartgrid session
 }
 
 // MetersConfig contains the site's meter configuration
@@ -113,35 +155,64 @@ type MetersConfig struct {
 
 // NewSiteFromConfig creates a new site
 func NewSiteFromConfig(other map[string]any) (*Site, error) {
+	return newSiteFromConfig(NewSite(), other)
+}
+
+// NewNamedSiteFromConfig creates an additional site. Its settings are stored separately by name.
+func NewNamedSiteFromConfig(name string, other map[string]any) (*Site, error) {
 	site := NewSite()
+	site.name = name
+	site.log = util.NewLogger(name)
+
+	return newSiteFromConfig(site, other)
+}
+
+func newSiteFromConfig(site *Site, other map[string]any) (*Site, error) {
 
 	// TODO remove
 	if err := util.DecodeOther(other, site); err != nil {
 		return nil, err
 	}
 
+	if site.Arbitrage != nil {
+		if site.Arbitrage.CycleCost == 0 {
+			site.Arbitrage.CycleCost = site.BatteryCycleCost
+		}
+
+		if err := site.Arbitrage.Validate(); err != nil {
+			return nil, fmt.Errorf("arbitrage: %w", err)
+		}
+	}
+
+	if site.OffGrid != nil {
+		offGridG, err := site.OffGrid.BoolGetter(util.WithLogger(context.TODO(), site.log))
+		if err != nil {
+			return nil, fmt.Errorf("offGrid: %w", err)
+		}
+		site.offGridG = offGridG
+	}
+
 	// add meters from config
 	site.restoreMetersAndTitle()
 
 	// TODO title
 	Voltage = site.Voltage
+	circuit.Voltage = site.Voltage
 
 	return site, nil
 }
 
-func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tariff.Tariffs) error {
+// Boot attaches loadpoints, tariffs and the vehicle coordinator shared by all sites
+func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tariff.Tariffs, coord *coordinator.Coordinator) error {
 	site.loadpoints = loadpoints
 	site.tariffs = tariffs
-
-	handler := config.Vehicles()
-	site.coordinator = coordinator.New(log, config.Instances(handler.Devices()))
-	handler.Subscribe(site.updateVehicles)
+	site.coordinator = coord
 
 	site.prioritizer = prioritizer.New(log)
 	site.stats = NewStats()
 
 	// upload telemetry on shutdown
-	if telemetry.Enabled() {
+	if telemetry.Enabled() && site.name == "" {
 		shutdown.Register(func() {
 			telemetry.Persist(log)
 		})
@@ -170,7 +241,13 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 	}
 
 	// circuit
-	if c := circuit.Root(); c != nil {
+	if site.CircuitRef != "" {
+		dev, err := config.Circuits().ByName(site.CircuitRef)
+		if err != nil {
+			return err
+		}
+		site.circuit = dev.Instance()
+	} else if c := circuit.Root(); c != nil {
 		site.circuit = c
 	}
 
@@ -195,7 +272,7 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 		site.pvMeters = append(site.pvMeters, dev)
 
 		// accumulator
-		site.pvEnergy[ref] = &meterEnergy{clock: clock.New()}
+		site.pvEnergy[ref] = &meterEnergy{clock: site.clock}
 	}
 
 	// multiple batteries
@@ -225,6 +302,11 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 		site.auxMeters = append(site.auxMeters, dev)
 	}
 
+	// release pv limit on shutdown
+	if site.FeedInLimit != nil {
+		shutdown.Register(site.releasePVLimit)
+	}
+
 	// revert battery mode on shutdown
 	shutdown.Register(func() {
 		if mode := site.GetBatteryMode(); batteryModeModified(mode) {
@@ -239,38 +321,56 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 
 // NewSite creates a Site with sane defaults
 func NewSite() *Site {
+	clock := clock.New()
+
 	site := &Site{
 		log:             util.NewLogger("site"),
+		clock:           clock,
 		Voltage:         230, // V
 		pvEnergy:        make(map[string]*meterEnergy),
-		fcstEnergy:      &meterEnergy{clock: clock.New()},
-		householdEnergy: &meterEnergy{clock: clock.New()},
+		batteryStats:    make(map[string]*batterystats.Stats),
+		fcstEnergy:      &meterEnergy{clock: clock},
+		householdEnergy: &meterEnergy{clock: clock},
+		pvLimit:         100, // %
 	}
 
 	return site
 }
 
+// Name returns the name of an additional site, empty for the main site
+func (site *Site) Name() string {
+	return site.name
+}
+
+// settingsKey returns the settings key, prefixed by name for additional sites
+func (site *Site) settingsKey(key string) string {
+	if site.name == "" {
+		return key
+	}
+	return "sites." + site.name + "." + key
+}
+
 // restoreMetersAndTitle restores site meter configuration
 func (site *Site) restoreMetersAndTitle() {
 	if testing.Testing() {
 		return
 	}
-	if v, err := settings.String(keys.Title); err == nil {
+	if v, err := settings.String(site.settingsKey(keys.Title)); err == nil {
 		site.Title = v
 	}
-	if v, err := settings.String(keys.GridMeter); err == nil && v != "" {
+	if v, err := settings.String(site.settingsKey(keys.GridMeter)); err == nil && v != "" {
 		site.Meters.GridMeterRef = v
 	}
-	if v, err := settings.String(keys.PvMeters); err == nil && v != "" {
+	if v, err := settings.String(site.settingsKey(keys.PvMeters)); err == nil && v != "" {
 		site.Meters.PVMetersRef = append(site.Meters.PVMetersRef, filterConfigurable(strings.Split(v, ","))...)
 	}
-	if v, err := settings.String(keys.BatteryMeters); err == nil && v != "" {
+	if v, err := settings.String(site.settingsKey(keys.BatteryMeters)); err == nil && v != "" {
 		site.Meters.BatteryMetersRef = append(site.Meters.BatteryMetersRef, filterConfigurable(strings.Split(v, ","))...)
 	}
-	if v, err := settings.String(keys.ExtMeters); err == nil && v != "" {
+	if v, err := settings.String(site.settingsKey(keys.ExtMeters)); err == nil && v != "" {
 		site.Meters.ExtMetersRef = append(site.Meters.ExtMetersRef, filterConfigurable(strings.Split(v, ","))...)
 	}
-	if v, err := settings.String(keys.AuxMeters); err == nil && v != "" {
+	if v, err := settings.String(site.settingsKey(keys.AuxMeters)); err == nil && v != "" {
 		site.Meters.AuxMetersRef = append(site.Meters.AuxMetersRef, filterConfigurable(strings.Split(v, ","))...)
 	}
 }
@@ -280,42 +380,53 @@ func (site *Site) restoreSettings() error {
 	if testing.Testing() {
 		return nil
 	}
-	if v, err := settings.Float(keys.BufferSoc); err == nil {
+	if v, err := settings.Float(site.settingsKey(keys.BufferSoc)); err == nil {
 		if err := site.SetBufferSoc(v); err != nil && !errors.Is(err, ErrBatteryNotConfigured) {
 			return err
 		}
 	}
-	if v, err := settings.Float(keys.BufferStartSoc); err == nil {
+	if v, err := settings.Float(site.settingsKey(keys.BufferStartSoc)); err == nil {
 		if err := site.SetBufferStartSoc(v); err != nil && !errors.Is(err, ErrBatteryNotConfigured) {
 			return err
 		}
 	}
-	if v, err := settings.Float(keys.PrioritySoc); err == nil {
+	if v, err := settings.Float(site.settingsKey(keys.PrioritySoc)); err == nil {
 		if err := site.SetPrioritySoc(v); err != nil && !errors.Is(err, ErrBatteryNotConfigured) {
 			return err
 		}
 	}
-	if v, err := settings.Bool(keys.BatteryDischargeControl); err == nil {
+	if v, err := settings.Bool(site.settingsKey(keys.BatteryDischargeControl)); err == nil {
 		if err := site.SetBatteryDischargeControl(v); err != nil && !errors.Is(err, ErrBatteryControlNotAvailable) {
 			return err
 		}
 	}
-	if v, err := settings.Float(keys.ResidualPower); err == nil {
+	if v, err := settings.Float(site.settingsKey(keys.ResidualPower)); err == nil {
 		if err := site.SetResidualPower(v); err != nil {
 			return err
 		}
 	}
-	if v, err := settings.Float(keys.BatteryGridChargeLimit); err == nil {
+	if v, err := settings.Float(site.settingsKey(keys.BatteryGridChargeLimit)); err == nil {
 		if err := site.SetBatteryGridChargeLimit(&v); err != nil && !errors.Is(err, ErrBatteryControlNotAvailable) {
 			return err
 		}
 	}
+	if v, err := settings.Float(site.settingsKey(keys.ArbitrageProfit)); err == nil {
+		site.arbitrageProfit = v
+	}
+	var stats map[string]*batterystats.Stats
+	if err := settings.Json(site.settingsKey(keys.BatteryStats), &stats); err == nil {
+		maps.Copy(site.batteryStats, stats)
+	}
+	var changes []api.ScheduledChange
+	if err := settings.Json(site.settingsKey(keys.Schedule), &changes); err == nil {
+		site.schedule.Restore(changes)
+	}
 
 	// restore accumulated energy
 	pvEnergy := make(map[string]meterEnergy)
-	fcstEnergy, err := settings.Float(keys.SolarAccForecast)
+	fcstEnergy, err := settings.Float(site.settingsKey(keys.SolarAccForecast))
 
-	if err == nil && settings.Json(keys.SolarAccYield, &pvEnergy) == nil {
+	if err == nil && settings.Json(site.settingsKey(keys.SolarAccYield), &pvEnergy) == nil {
 		var nok bool
 		for _, name := range site.Meters.PVMetersRef {
 			if fcst, ok := pvEnergy[name]; ok {
@@ -333,8 +444,8 @@ func (site *Site) restoreSettings() error {
 			// reset metrics
 			site.log.WARN.Printf("accumulated solar yield: metrics reset")
 
-			settings.Delete(keys.SolarAccForecast)
-			settings.Delete(keys.SolarAccYield)
+			settings.Delete(site.settingsKey(keys.SolarAccForecast))
+			settings.Delete(site.settingsKey(keys.SolarAccYield))
 
 			for _, pe := range site.pvEnergy {
 				pe.Accumulated = 0
@@ -552,6 +663,9 @@ func (site *Site) updatePvMeters() {
 		}
 	}
 
+	site.pvPowers = lo.Map(mm, func(m types.Measurement, _ int) float64 {
+		return m.Power
+	})
 	site.pvPower = lo.SumBy(mm, func(m types.Measurement) float64 {
 		return max(0, m.Power)
 	})
@@ -592,7 +706,7 @@ func (site *Site) updatePvMeters() {
 	}
 
 	// store
-	if err := settings.SetJson(keys.SolarAccYield, site.pvEnergy); err != nil {
+	if err := settings.SetJson(site.settingsKey(keys.SolarAccYield), site.pvEnergy); err != nil {
 		site.log.ERROR.Println("accumulated solar production:", err)
 		for k, v := range site.pvEnergy {
 			site.log.ERROR.Printf("!! %s: %+v", k, v)
@@ -662,6 +776,8 @@ func (site *Site) updateBatteryMeters() {
 	site.battery.Devices = mm
 
 	site.publish(keys.Battery, site.battery)
+
+	site.updateBatteryStats(mm)
 }
 
 func sumOfSocs(mm []types.Measurement) float64 {
@@ -720,6 +836,11 @@ func (site *Site) updateGridMeter() error {
 
 	var mm types.Measurement
 
+	defer func() {
+		site.gridPowers = mm.Powers
+		site.gridCurrents = mm.Currents
+	}()
+
 	if res, err := backoff.RetryWithData(site.gridMeter.CurrentPower, modbus.Backoff()); err == nil {
 		mm.Power = res
 		site.gridPower = res
@@ -745,6 +866,10 @@ func (site *Site) updateGridMeter() error {
 		if i1, i2, i3, err := phaseMeter.Currents(); err == nil {
 			mm.Currents = []float64{util.SignFromPower(i1, p1), util.SignFromPower(i2, p2), util.SignFromPower(i3, p3)}
 			site.log.DEBUG.Printf("grid currents: %.3gA", mm.Currents)
+
+			imbalance := max(i1, i2, i3) - min(i1, i2, i3)
+			site.log.DEBUG.Printf("phase imbalance: %.3gA", imbalance)
+			site.publish(keys.PhaseImbalance, imbalance)
 		} else {
 			site.log.ERROR.Printf("grid currents: %v", err)
 		}
@@ -908,9 +1033,16 @@ func (site *Site) updateLoadpoints(rates api.Rates) float64 {
 	return sum
 }
 
-func (site *Site) update(lp updater) {
+// update reads all meters and updates the given loadpoints, allocating site power jointly
+func (site *Site) update(lps ...updater) {
 	site.log.DEBUG.Println("----")
 
+	// scheduled setting changes
+	site.applySchedule()
+
+	// off-grid operation
+	site.updateOffGrid()
+
 	// smart cost and battery mode handling
 	consumption, err := site.tariffRates(api.TariffUsagePlanner)
 	if err != nil {
@@ -952,14 +1084,15 @@ func (site *Site) update(lp updater) {
 
 	// prioritize if possible
 	var flexiblePower float64
-	if lp != nil && lp.GetMode() == api.ModePV {
-		flexiblePower = site.prioritizer.GetChargePowerFlexibility(lp)
+	if len(lps) == 1 && lps[0].GetMode() == api.ModePV {
+		flexiblePower = site.prioritizer.GetChargePowerFlexibility(lps[0])
 	}
 
 	if sitePower, batteryBuffered, batteryStart, err := site.sitePower(totalChargePower, flexiblePower); err == nil {
 		// ignore negative pvPower values as that means it is not an energy source but consumption
 		homePower := site.gridPower + max(0, site.pvPower) + site.battery.Power - totalChargePower
 		homePower = max(homePower, 0)
+		site.homePower = homePower
 		site.publish(keys.HomePower, homePower)
 
 		if homePower > 0 {
@@ -972,16 +1105,23 @@ func (site *Site) update(lp updater) {
 		greenShareHome := site.greenShare(0, homePower)
 		greenShareLoadpoints := site.greenShare(nonChargePower, nonChargePower+totalChargePower)
 
-		// TODO
-		if lp != nil {
-			lp.Update(
-				sitePower, max(0, site.battery.Power), consumption, feedin, batteryBuffered, batteryStart,
-				greenShareLoadpoints, site.effectivePrice(greenShareLoadpoints), site.effectiveCo2(greenShareLoadpoints),
-			)
+		// no battery support for loadpoints in off-grid operation
+		batteryBoostPower := max(0, site.battery.Power)
+		if site.GetOffGrid() {
+			batteryBoostPower, batteryBuffered, batteryStart = 0, false, false
 		}
 
+		site.updateLoadpointSetpoints(
+			lps, sitePower, batteryBoostPower, consumption, feedin, batteryBuffered, batteryStart,
+			greenShareLoadpoints, site.effectivePrice(greenShareLoadpoints), site.effectiveCo2(greenShareLoadpoints),
+		)
+
 		site.publishTariffs(greenShareHome, greenShareLoadpoints)
 
+		if site.FeedInLimit != nil {
+			site.limitFeedIn(*site.FeedInLimit)
+		}
+
 		if telemetry.Enabled() && totalChargePower > standbyPower {
 			go telemetry.UpdateChargeProgress(site.log, totalChargePower, greenShareLoadpoints)
 		}
@@ -990,9 +1130,9 @@ func (site *Site) update(lp updater) {
 	}
 
 	// smart grid charging
-	rate, err := consumption.At(time.Now())
+	rate, err := consumption.At(site.clock.Now())
 	if consumption != nil && err != nil {
-		msg := fmt.Sprintf("no matching rate for: %s", time.Now().Format(time.RFC3339))
+		msg := fmt.Sprintf("no matching rate for: %s", site.clock.Now().Format(time.RFC3339))
 		if len(consumption) > 0 {
 			msg += fmt.Sprintf(", %d consumption rates (%s to %s)", len(consumption),
 				consumption[0].Start.Local().Format(time.RFC3339),
@@ -1004,13 +1144,34 @@ func (site *Site) update(lp updater) {
 	}
 
 	// update battery after reading meters to ensure that (modbus) connection is open
-	batteryGridChargeActive := site.batteryGridChargeActive(rate)
+	site.updateArbitrage(feedin)
+	batteryGridChargeActive := site.batteryGridChargeActive(rate) || site.arbitrageAction == arbitrage.Charge
 	site.publish(keys.BatteryGridChargeActive, batteryGridChargeActive)
+	site.updateBatteryPower(batteryGridChargeActive, rate)
 	site.updateBatteryMode(batteryGridChargeActive, rate)
 
 	site.stats.Update(site)
 }
 
+// updateLoadpointSetpoints updates the loadpoints in parallel using their share of site power
+func (site *Site) updateLoadpointSetpoints(lps []updater, sitePower, batteryBoostPower float64, consumption, feedin api.Rates, batteryBuffered, batteryStart bool, greenShare float64, effectivePrice, effectiveCo2 *float64) {
+	shares := allocateSitePower(sitePower, site.phaseBudget(sitePower), lo.Map(lps, func(lp updater, _ int) loadpoint.API { return lp }))
+
+	var wg sync.WaitGroup
+
+	for i, lp := range lps {
+		if shares[i] != sitePower {
+			site.log.DEBUG.Printf("lp %s: allocated site power %.0fW", lp.GetTitle(), shares[i])
+		}
+
+		wg.Go(func() {
+			lp.Update(shares[i], batteryBoostPower, consumption, feedin, batteryBuffered, batteryStart, greenShare, effectivePrice, effectiveCo2)
+		})
+	}
+
+	wg.Wait()
+}
+
 // prepare publishes initial values
 func (site *Site) prepare() {
 	if err := site.restoreSettings(); err != nil {
@@ -1031,6 +1192,7 @@ func (site *Site) prepare() {
 	site.publish(keys.BatteryMode, site.batteryMode)
 	site.publish(keys.BatteryDischargeControl, site.batteryDischargeControl)
 	site.publish(keys.ResidualPower, site.GetResidualPower())
+	site.schedule.Publish(site.scheduleOwner(), site.clock.Now())
 	site.publish(keys.SmartCostAvailable, site.isDynamicTariff(api.TariffUsagePlanner))
 	site.publish(keys.SmartFeedInPriorityAvailable, site.isDynamicTariff(api.TariffUsageFeedIn))
 
@@ -1043,8 +1205,9 @@ func (site *Site) prepare() {
 
 	site.publishVehicles()
 	site.publishTariffs(0, 0)
-	vehicle.Publish = site.publishVehicles
-	vehicle.ClearPlanLocks = site.clearPlanLocks
+
+	vehicle.OnPublish(site.publishVehicles)
+	vehicle.OnClearPlanLocks(site.clearPlanLocks)
 }
 
 // Prepare attaches communication channels to site and loadpoints
@@ -1061,10 +1224,12 @@ func (site *Site) Prepare(valueChan chan<- util.Param, pushChan chan<- messenger
 	// use ch.Out for reading
 	go func() {
 		for p := range ch.Out {
+			p.Site = site.name
 			valueChan <- p
 		}
 	}()
 
+	site.pushChan = pushChan
 	site.lpUpdateChan = make(chan *Loadpoint, 1) // 1 capacity to avoid deadlock
 
 	site.prepare()
@@ -1081,6 +1246,7 @@ func (site *Site) Prepare(valueChan chan<- util.Param, pushChan chan<- messenger
 					param.Loadpoint = &id
 					site.valueChan <- param
 				case ev := <-lpPushChan:
+					ev.Site = site.name
 					ev.Loadpoint = &id
 					pushChan <- ev
 				}
@@ -1091,22 +1257,9 @@ func (site *Site) Prepare(valueChan chan<- util.Param, pushChan chan<- messenger
 	}
 }
 
-// loopLoadpoints keeps iterating across loadpoints sending the next to the given channel
-func (site *Site) loopLoadpoints(next chan<- updater) {
-	var logOnce sync.Once
-
-	for {
-		if len(site.loadpoints) == 0 {
-			logOnce.Do(func() {
-				site.log.INFO.Println("no loadpoints configured, running in meter-only mode")
-			})
-			next <- nil
-		} else {
-			for _, lp := range site.loadpoints {
-				next <- lp
-			}
-		}
-	}
+// updaters returns all loadpoints for updating
+func (site *Site) updaters() []updater {
+	return lo.Map(site.loadpoints, func(lp *Loadpoint, _ int) updater { return lp })
 }
 
 // Run is the main control loop. It reacts to trigger events by
@@ -1116,17 +1269,31 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 		site.log.INFO.Printf("interval <%.0fs can lead to unexpected behavior, see https://docs.evcc.io/docs/reference/configuration/interval", max.Seconds())
 	}
 
-	loadpointChan := make(chan updater)
-	if site.IsConfigured() {
-		go site.loopLoadpoints(loadpointChan)
+	// nothing to control
+	if !site.IsConfigured() {
+		<-stopC
+		return
+	}
+
+	if len(site.loadpoints) == 0 {
+		site.log.INFO.Println("no loadpoints configured, running in meter-only mode")
 	}
 
-	site.update(<-loadpointChan) // start immediately
+	site.update(site.updaters()...) // start immediately
+
+	// grid meter pushing values triggers updates
+	var gridUpdated <-chan struct{}
+	if m, ok := api.Cap[api.MeterUpdater](site.gridMeter); ok {
+		site.log.DEBUG.Println("grid meter: push updates enabled")
+		gridUpdated = m.Updated()
+	}
 
 	for tick := time.Tick(interval); ; {
 		select {
 		case <-tick:
-			site.update(<-loadpointChan)
+			site.update(site.updaters()...)
+		case <-gridUpdated:
+			site.update(site.updaters()...)
 		case lp := <-site.lpUpdateChan:
 			site.update(lp)
 		case <-stopC:
@@ -2,6 +2,7 @@ package site
 
 import (
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/batterystats"
 	"github.com/evcc-io/evcc/core/loadpoint"
 )
 
@@ -37,6 +38,9 @@ type API interface {
 	GetCircuit() api.Circuit
 	SetCircuit(api.Circuit)
 
+	// GetOffGrid returns true if the site is in off-grid operation
+	GetOffGrid() bool
+
 	//
 	// battery
 	//
@@ -49,6 +53,9 @@ type API interface {
 	GetBufferStartSoc() float64
 	SetBufferStartSoc(float64) error
 
+	// GetBatteryStats returns the cycle and degradation statistics by battery meter name
+	GetBatteryStats() map[string]batterystats.Stats
+
 	// GetBatteryGridChargeLimit get the grid charge limit
 	GetBatteryGridChargeLimit() *float64
 	// SetBatteryGridChargeLimit sets the grid charge limit
@@ -75,6 +82,9 @@ type API interface {
 	GetBatteryDischargeControl() bool
 	SetBatteryDischargeControl(bool) error
 
+	// GetBatteryMode returns the battery mode
+	GetBatteryMode() api.BatteryMode
+
 	//
 	// battery control external
 	//
@@ -83,4 +93,13 @@ type API interface {
 	GetBatteryModeExternal() api.BatteryMode
 	// SetBatteryModeExternal sets the external battery mode
 	SetBatteryModeExternal(api.BatteryMode) error
+
+	//
+	// scheduled changes
+	//
+
+	// GetSchedule returns the scheduled setting changes
+	GetSchedule() []api.ScheduledChange
+	// SetSchedule sets the scheduled setting changes
+	SetSchedule([]api.ScheduledChange) error
 }
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+// phaseBudget is the site power and circuit headroom per phase
+type phaseBudget struct {
+	power    [3]float64 // site power per phase
+	headroom [3]float64 // power until reaching the circuit's max current per phase
+}
+
+// phaseBudget returns the site's per-phase budget if grid import and export are metered per phase
+func (site *Site) phaseBudget(sitePower float64) *phaseBudget {
+	if !site.PhaseMetering || len(site.gridPowers) != 3 {
+		return nil
+	}
+
+	res := new(phaseBudget)
+
+	var maxCurrent float64
+	if site.circuit != nil {
+		maxCurrent = site.circuit.GetMaxCurrent()
+	}
+
+	for i, p := range site.gridPowers {
+		// remaining site power contributions are distributed evenly
+		res.power[i] = p + (sitePower-site.gridPower)/3
+
+		res.headroom[i] = math.MaxFloat64
+		if maxCurrent > 0 && len(site.gridCurrents) == 3 {
+			res.headroom[i] = max(0, maxCurrent-site.gridCurrents[i]) * Voltage
+		}
+	}
+
+	return res
+}
+
+// allocateSitePower distributes site power across loadpoints. Surplus (negative site power)
+// is handed to loadpoints by descending priority up to their remaining power, deficit is taken
+// from loadpoints by ascending priority up to their charge power.
+// If phase budget is given, single-phase loadpoints with known grid phase receive their phase's
+// site power. Multi-phase loadpoints are limited by the phase with highest import.
+// Loadpoints not charging from pv receive the unchanged site power.
+func allocateSitePower(sitePower float64, budget *phaseBudget, lps []loadpoint.API) []float64 {
+	res := make([]float64, len(lps))
+
+	var pv []int
+	for i, lp := range lps {
+		res[i] = sitePower
+
+		if mode := lp.GetMode(); (mode == api.ModePV || mode == api.ModeMinPV) && lp.GetStatus() != api.StatusA {
+			pv = append(pv, i)
+		}
+	}
+
+	// highest priority first, keep configured order otherwise
+	slices.SortStableFunc(pv, func(i, j int) int {
+		return cmp.Compare(lps[j].EffectivePriority(), lps[i].EffectivePriority())
+	})
+
+	if budget == nil {
+		allocate(sitePower, math.MaxFloat64, pv, lps, res)
+		return res
+	}
+
+	var (
+		single [3][]int
+		multi  []int
+	)
+
+	for _, i := range pv {
+		if phase := lps[i].GetGridPhase(); phase > 0 && lps[i].ActivePhases() == 1 {
+			single[phase-1] = append(single[phase-1], i)
+		} else {
+			multi = append(multi, i)
+		}
+	}
+
+	power, headroom := budget.power, budget.headroom
+
+	for phase, idx := range single {
+		used := allocate(power[phase], headroom[phase], idx, lps, res)
+		power[phase] -= used
+		headroom[phase] += used
+	}
+
+	allocate(3*max(power[0], power[1], power[2]), 3*min(headroom[0], headroom[1], headroom[2]), multi, lps, res)
+
+	return res
+}
+
+// allocate distributes power across the prioritized loadpoints, limiting the total increase to headroom.
+// It returns the allocated power.
+func allocate(power, headroom float64, idx []int, lps []loadpoint.API, res []float64) float64 {
+	if power > 0 {
+		idx = slices.Clone(idx)
+		slices.Reverse(idx)
+	} else {
+		power = -min(-power, headroom)
+	}
+
+	remaining := power
+
+	for n, i := range idx {
+		lp := lps[i]
+
+		var share float64
+		if power <= 0 {
+			share = -min(-remaining, max(0, lp.EffectiveMaxPower()-lp.GetChargePower()))
+		} else {
+			share = min(remaining, lp.GetChargePower())
+		}
+
+		// last loadpoint receives what is left
+		if n == len(idx)-1 {
+			share = remaining
+		}
+
+		res[i] = share
+		remaining -= share
+	}
+
+	return power - remaining
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAllocateSitePower(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	newLp := func(mode api.ChargeMode, prio int, chargePower, maxPower float64) *loadpoint.MockAPI {
+		lp := loadpoint.NewMockAPI(ctrl)
+		lp.EXPECT().GetMode().Return(mode).AnyTimes()
+		lp.EXPECT().GetStatus().Return(api.StatusC).AnyTimes()
+		lp.EXPECT().EffectivePriority().Return(prio).AnyTimes()
+		lp.EXPECT().GetChargePower().Return(chargePower).AnyTimes()
+		lp.EXPECT().EffectiveMaxPower().Return(maxPower).AnyTimes()
+		return lp
+	}
+
+	lo := newLp(api.ModePV, 0, 2000, 11000)
+	hi := newLp(api.ModePV, 1, 10000, 11000)
+	now := newLp(api.ModeNow, 0, 11000, 11000)
+
+	for _, tc := range []struct {
+		sitePower float64
+		expected  []float64
+	}{
+		// surplus goes to higher priority first
+		{-3000, []float64{-2000, -1000, -3000}},
+		{-500, []float64{0, -500, -500}},
+		// deficit is taken from lower priority first
+		{1000, []float64{1000, 0, 1000}},
+		{5000, []float64{2000, 3000, 5000}},
+		{0, []float64{0, 0, 0}},
+	} {
+		assert.Equal(t, tc.expected, allocateSitePower(tc.sitePower, nil, []loadpoint.API{lo, hi, now}), tc.sitePower)
+	}
+}
+
+func TestAllocateSitePowerPhases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	newLp := func(phase, phases int, chargePower float64) *loadpoint.MockAPI {
+		lp := loadpoint.NewMockAPI(ctrl)
+		lp.EXPECT().GetMode().Return(api.ModePV).AnyTimes()
+		lp.EXPECT().GetStatus().Return(api.StatusC).AnyTimes()
+		lp.EXPECT().EffectivePriority().Return(0).AnyTimes()
+		lp.EXPECT().GetChargePower().Return(chargePower).AnyTimes()
+		lp.EXPECT().EffectiveMaxPower().Return(float64(phases) * 16 * 230).AnyTimes()
+		lp.EXPECT().GetGridPhase().Return(phase).AnyTimes()
+		lp.EXPECT().ActivePhases().Return(phases).AnyTimes()
+		return lp
+	}
+
+	l1 := newLp(1, 1, 0)
+	l3 := newLp(3, 1, 1000)
+	three := newLp(0, 3, 0)
+
+	// export on L1, import on L3
+	budget := &phaseBudget{
+		power:    [3]float64{-2000, -500, 300},
+		headroom: [3]float64{math.MaxFloat64, math.MaxFloat64, math.MaxFloat64},
+	}
+
+	// total site power would only allow 2200W, single-phase loadpoints use their phase's surplus
+	res := allocateSitePower(-2200, budget, []loadpoint.API{l1, l3, three})
+	assert.Equal(t, []float64{-2000, 300, 0}, res)
+
+	// circuit limits single-phase increase on L1
+	budget.headroom[0] = 1500
+	res = allocateSitePower(-2200, budget, []loadpoint.API{l1, l3, three})
+	assert.Equal(t, []float64{-1500, 300, 0}, res)
+}
@@ -49,7 +49,7 @@ func (site *Site) SetTitle(title string) {
 
 	site.Title = title
 	site.publish(keys.SiteTitle, title)
-	settings.SetString(keys.Title, title)
+	settings.SetString(site.settingsKey(keys.Title), title)
 }
 
 // GetGridMeterRef returns the GridMeterRef
@@ -65,7 +65,7 @@ func (site *Site) SetGridMeterRef(ref string) {
 	defer site.Unlock()
 
 	site.Meters.GridMeterRef = ref
-	settings.SetString(keys.GridMeter, ref)
+	settings.SetString(site.settingsKey(keys.GridMeter), ref)
 }
 
 // GetPVMeterRefs returns the PvMeterRef
@@ -81,7 +81,7 @@ func (site *Site) SetPVMeterRefs(ref []string) {
 	defer site.Unlock()
 
 	site.Meters.PVMetersRef = ref
-	settings.SetString(keys.PvMeters, strings.Join(filterConfigurable(ref), ","))
+	settings.SetString(site.settingsKey(keys.PvMeters), strings.Join(filterConfigurable(ref), ","))
 }
 
 // GetBatteryMeterRefs returns the BatteryMeterRef
@@ -97,7 +97,7 @@ func (site *Site) SetBatteryMeterRefs(ref []string) {
 	defer site.Unlock()
 
 	site.Meters.BatteryMetersRef = ref
-	settings.SetString(keys.BatteryMeters, strings.Join(filterConfigurable(ref), ","))
+	settings.SetString(site.settingsKey(keys.BatteryMeters), strings.Join(filterConfigurable(ref), ","))
 }
 
 // GetAuxMeterRefs returns the AuxMeterRef
@@ -113,7 +113,7 @@ func (site *Site) SetAuxMeterRefs(ref []string) {
 	defer site.Unlock()
 
 	site.Meters.AuxMetersRef = ref
-	settings.SetString(keys.AuxMeters, strings.Join(filterConfigurable(ref), ","))
+	settings.SetString(site.settingsKey(keys.AuxMeters), strings.Join(filterConfigurable(ref), ","))
 }
 
 // GetExtMeterRefs returns the ExtMeterRef
@@ -129,7 +129,7 @@ func (site *Site) SetExtMeterRefs(ref []string) {
 	defer site.Unlock()
 
 	site.Meters.ExtMetersRef = ref
-	settings.SetString(keys.ExtMeters, strings.Join(filterConfigurable(ref), ","))
+	settings.SetString(site.settingsKey(keys.ExtMeters), strings.Join(filterConfigurable(ref), ","))
 }
 
 // GetBatterySoc returns the current battery soc
@@ -200,7 +200,7 @@ func (site *Site) SetPrioritySoc(soc float64) error {
 
 	if site.prioritySoc != soc {
 		site.prioritySoc = soc
-		settings.SetFloat(keys.PrioritySoc, site.prioritySoc)
+		settings.SetFloat(site.settingsKey(keys.PrioritySoc), site.prioritySoc)
 		site.publish(keys.PrioritySoc, site.prioritySoc)
 	}
 
@@ -235,7 +235,7 @@ func (site *Site) SetBufferSoc(soc float64) error {
 
 	if site.bufferSoc != soc {
 		site.bufferSoc = soc
-		settings.SetFloat(keys.BufferSoc, site.bufferSoc)
+		settings.SetFloat(site.settingsKey(keys.BufferSoc), site.bufferSoc)
 		site.publish(keys.BufferSoc, site.bufferSoc)
 	}
 
@@ -266,7 +266,7 @@ func (site *Site) SetBufferStartSoc(soc float64) error {
 
 	if site.bufferStartSoc != soc {
 		site.bufferStartSoc = soc
-		settings.SetFloat(keys.BufferStartSoc, site.bufferStartSoc)
+		settings.SetFloat(site.settingsKey(keys.BufferStartSoc), site.bufferStartSoc)
 		site.publish(keys.BufferStartSoc, site.bufferStartSoc)
 	}
 
@@ -289,7 +289,7 @@ func (site *Site) SetResidualPower(power float64) error {
 
 	if site.ResidualPower != power {
 		site.ResidualPower = power
-		settings.SetFloat(keys.ResidualPower, site.ResidualPower)
+		settings.SetFloat(site.settingsKey(keys.ResidualPower), site.ResidualPower)
 		site.publish(keys.ResidualPower, site.ResidualPower)
 	}
 
@@ -323,7 +323,7 @@ func (site *Site) SetBatteryDischargeControl(val bool) error {
 
 	if site.batteryDischargeControl != val {
 		site.batteryDischargeControl = val
-		settings.SetBool(keys.BatteryDischargeControl, val)
+		settings.SetBool(site.settingsKey(keys.BatteryDischargeControl), val)
 		site.publish(keys.BatteryDischargeControl, val)
 	}
 
@@ -350,10 +350,10 @@ func (site *Site) SetBatteryGridChargeLimit(val *float64) error {
 		site.batteryGridChargeLimit = val
 
 		if val == nil {
-			settings.SetString(keys.BatteryGridChargeLimit, "")
+			settings.SetString(site.settingsKey(keys.BatteryGridChargeLimit), "")
 			site.publish(keys.BatteryGridChargeLimit, nil)
 		} else {
-			settings.SetFloat(keys.BatteryGridChargeLimit, *val)
+			settings.SetFloat(site.settingsKey(keys.BatteryGridChargeLimit), *val)
 			site.publish(keys.BatteryGridChargeLimit, *val)
 		}
 	}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/arbitrage"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+type arbitrageStruct struct {
+	Action   arbitrage.Action   `json:"action"`
+	Windows  []arbitrage.Window `json:"windows"`
+	Expected float64            `json:"expected"` // expected profit of planned windows
+	Profit   float64            `json:"profit"`   // achieved profit
+}
+
+// accountArbitrage adds the profit of the active arbitrage action since the last update.
+// Only energy exchanged with the grid by the battery while the arbitrage setpoint was applied is accounted.
+func (site *Site) accountArbitrage(grid, feedIn api.Rates, now time.Time) {
+	if site.arbitrageUpdated.IsZero() || site.batterySetpoint == nil {
+		return
+	}
+
+	hours := now.Sub(site.arbitrageUpdated).Hours()
+
+	switch site.arbitrageAction {
+	case arbitrage.Charge:
+		// grid import charging the battery, pv charging is free
+		energy := min(max(0, site.gridPower), max(0, -site.battery.Power)) / 1e3 * hours
+		if r, err := grid.At(now); err == nil {
+			site.arbitrageProfit -= energy * r.Value
+		}
+	case arbitrage.Discharge:
+		// battery export, supplying the house is not exported
+		energy := min(max(0, -site.gridPower), max(0, site.battery.Power)) / 1e3 * hours
+		if r, err := feedIn.At(now); err == nil {
+			site.arbitrageProfit += energy * r.Value
+		}
+	default:
+		return
+	}
+
+	settings.SetFloat(site.settingsKey(keys.ArbitrageProfit), site.arbitrageProfit)
+}
+
+// updateArbitrage plans battery charge and discharge windows from grid and feed-in rates
+func (site *Site) updateArbitrage(feedIn api.Rates) {
+	if site.Arbitrage == nil {
+		return
+	}
+
+	grid, err := site.tariffRates(api.TariffUsageGrid)
+	if err != nil {
+		site.log.WARN.Println("arbitrage:", err)
+	}
+
+	now := site.clock.Now()
+
+	site.accountArbitrage(grid, feedIn, now)
+	site.arbitrageUpdated = now
+
+	reserve := site.Arbitrage.ReserveSoc
+	capacity := site.battery.Capacity
+
+	stored := max(0, capacity*(site.battery.Soc-reserve)/100)
+	usable := capacity * (100 - reserve) / 100
+
+	windows, expected := arbitrage.Plan(*site.Arbitrage, grid, feedIn, now, stored, usable)
+
+	action := arbitrage.At(windows, now)
+	switch {
+	case action == arbitrage.Idle:
+	case len(site.batteryPowerControllers()) == 0:
+		site.log.DEBUG.Println("arbitrage: requires battery power control")
+		action = arbitrage.Idle
+	case action == arbitrage.Discharge && site.battery.Soc <= reserve:
+		site.log.DEBUG.Printf("arbitrage: reserve soc reached (%.0f%% <= %.0f%%)", site.battery.Soc, reserve)
+		action = arbitrage.Idle
+	}
+
+	if action != site.arbitrageAction {
+		site.log.DEBUG.Printf("arbitrage: %s", action)
+	}
+
+	site.arbitrageAction = action
+
+	site.publish(keys.Arbitrage, arbitrageStruct{
+		Action:   action,
+		Windows:  windows,
+		Expected: expected,
+		Profit:   site.arbitrageProfit,
+	})
+}
+
+// arbitragePowerSetpoint returns the battery power setpoint of the active arbitrage action
+func (site *Site) arbitragePowerSetpoint() *float64 {
+	switch site.arbitrageAction {
+	case arbitrage.Charge:
+		return new(-site.Arbitrage.Power)
+	case arbitrage.Discharge:
+		return new(site.Arbitrage.Power)
+	default:
+		return nil
+	}
+}
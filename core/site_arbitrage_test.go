@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/arbitrage"
+	"github.com/evcc-io/evcc/core/types"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArbitrageProfit(t *testing.T) {
+	now := time.Now()
+	rates := func(v float64) api.Rates {
+		return api.Rates{{Start: now.Add(-time.Hour), End: now.Add(time.Hour), Value: v}}
+	}
+
+	site := &Site{
+		log:       util.NewLogger("foo"),
+		Arbitrage: &arbitrage.Config{Power: 2000},
+		battery:   types.BatteryState{Power: -2000},
+	}
+
+	account := func(grid, feedIn float64) {
+		site.arbitrageUpdated = now.Add(-time.Hour)
+		site.accountArbitrage(rates(grid), rates(feedIn), now)
+	}
+
+	// charging from grid
+	site.arbitrageAction = arbitrage.Charge
+	site.batterySetpoint = site.batteryPowerSetpoint(false, api.Rate{})
+	assert.Equal(t, new(-2000.0), site.batterySetpoint)
+
+	site.gridPower = 2500
+	account(0.1, 0.05)
+	assert.InDelta(t, -0.2, site.arbitrageProfit, 1e-9)
+
+	// charging from pv is free
+	site.gridPower = -500
+	account(0.1, 0.05)
+	assert.InDelta(t, -0.2, site.arbitrageProfit, 1e-9)
+
+	// exporting at feed-in price
+	site.battery.Power = 2000
+	site.arbitrageAction = arbitrage.Discharge
+	site.batterySetpoint = site.batteryPowerSetpoint(false, api.Rate{})
+	assert.Equal(t, new(2000.0), site.batterySetpoint)
+
+	site.gridPower = -2000
+	account(0.1, 0.4)
+	assert.InDelta(t, 0.6, site.arbitrageProfit, 1e-9)
+
+	// supplying the house is not exported
+	site.gridPower = -500
+	account(0.1, 0.4)
+	assert.InDelta(t, 0.8, site.arbitrageProfit, 1e-9)
+
+	// not applied
+	site.batterySetpoint = nil
+	site.gridPower = -2000
+	account(0.1, 0.4)
+	assert.InDelta(t, 0.8, site.arbitrageProfit, 1e-9)
+
+	// idle
+	site.arbitrageAction = arbitrage.Idle
+	account(0.1, 0.4)
+	assert.InDelta(t, 0.8, site.arbitrageProfit, 1e-9)
+	assert.Nil(t, site.batteryPowerSetpoint(false, api.Rate{}))
+}
+
+func TestArbitrageDischargeControl(t *testing.T) {
+	lp := NewLoadpoint(util.NewLogger("foo"), nil)
+	lp.mode = api.ModeNow
+	lp.status = api.StatusC
+
+	site := &Site{
+		log:             util.NewLogger("foo"),
+		Arbitrage:       &arbitrage.Config{Power: 2000},
+		arbitrageAction: arbitrage.Discharge,
+		loadpoints:      []*Loadpoint{lp},
+		homePower:       800,
+	}
+
+	// battery may feed the vehicle without discharge control
+	assert.Equal(t, new(2000.0), site.batteryPowerSetpoint(false, api.Rate{}))
+
+	// limited to house consumption while fast charging
+	site.batteryDischargeControl = true
+	assert.Equal(t, new(800.0), site.batteryPowerSetpoint(false, api.Rate{}))
+
+	// charging unaffected
+	site.arbitrageAction = arbitrage.Charge
+	assert.Equal(t, new(-2000.0), site.batteryPowerSetpoint(false, api.Rate{}))
+}
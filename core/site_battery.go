@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -14,8 +15,9 @@ func batteryModeModified(mode api.BatteryMode) bool {
 	return mode != api.BatteryUnknown && mode != api.BatteryNormal
 }
 
+// batteryConfigured returns true if home batteries or vehicles used as home battery are configured
 func (site *Site) batteryConfigured() bool {
-	return len(site.batteryMeters) > 0
+	return len(site.batteryMeters) > 0 || slices.ContainsFunc(site.loadpoints, (*Loadpoint).v2hAvailable)
 }
 
 func (site *Site) hasBatteryControl() bool {
@@ -94,6 +96,11 @@ func (site *Site) requiredBatteryMode(batteryGridChargeActive bool, rate api.Rat
 	switch {
 	case !site.batteryConfigured():
 		res = api.BatteryUnknown
+	case site.GetOffGrid():
+		// battery must supply the house in off-grid operation
+		if batteryModeModified(batMode) {
+			res = api.BatteryNormal
+		}
 	case extModeReset:
 		// require normal mode to leave external control
 		res = api.BatteryNormal
@@ -153,7 +160,7 @@ func (site *Site) applyBatteryMode(mode api.BatteryMode) error {
 		meter := dev.Instance()
 
 		batCtrl, ok := api.Cap[api.BatteryController](meter)
-		if !ok {
+		if !ok || site.batteryPowerControlled(dev) {
 			continue
 		}
 
@@ -209,7 +216,7 @@ func (site *Site) dischargeControlActive(rate api.Rate) bool {
 
 	for _, lp := range site.Loadpoints() {
 		smartCostActive := site.smartCostActive(lp, rate)
-		if lp.GetStatus() == api.StatusC && (smartCostActive || lp.IsFastChargingActive()) {
+		if lp.GetStatus() == api.StatusC && (smartCostActive || lp.IsFastChargingActive() || site.batteryWearExceedsGrid(rate)) {
 			return true
 		}
 	}
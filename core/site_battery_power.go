@@ -0,0 +1,284 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/util/config"
+	optimizer "github.com/evcc-io/optimizer/client"
+)
+
+// batteryTarget is the home battery power target of an optimizer slot
+type batteryTarget struct {
+	Start, End time.Time
+	Power      float64 // positive for discharging, negative for charging
+}
+
+// batteryShare holds the properties of a battery sharing the power setpoint
+type batteryShare struct {
+	soc               float64
+	charge, discharge float64 // power limits, 0 if unlimited
+}
+
+// distributeBatteryPower splits the power setpoint across batteries. Discharging is weighted by soc, charging by
+// remaining capacity. Batteries reaching their power limit are capped and the remainder is shared by the others.
+func distributeBatteryPower(total float64, batteries []batteryShare) []float64 {
+	res := make([]float64, len(batteries))
+
+	weight := func(b batteryShare) float64 {
+		if total < 0 {
+			return max(0, 100-b.soc)
+		}
+		return max(0, b.soc)
+	}
+
+	limit := func(b batteryShare) float64 {
+		if total < 0 {
+			return b.charge
+		}
+		return b.discharge
+	}
+
+	open := make([]int, 0, len(batteries))
+	for i, b := range batteries {
+		if weight(b) > 0 {
+			open = append(open, i)
+		}
+	}
+
+	remaining := math.Abs(total)
+
+	for remaining > 0 && len(open) > 0 {
+		var sum float64
+		for _, i := range open {
+			sum += weight(batteries[i])
+		}
+
+		var uncapped []int
+		for _, i := range open {
+			if l := limit(batteries[i]); l > 0 && remaining*weight(batteries[i])/sum > l {
+				res[i] = l
+				continue
+			}
+			uncapped = append(uncapped, i)
+		}
+
+		// no battery capped, distribute remainder
+		if len(uncapped) == len(open) {
+			for _, i := range open {
+				res[i] = remaining * weight(batteries[i]) / sum
+			}
+			break
+		}
+
+		for _, i := range open {
+			if !slices.Contains(uncapped, i) {
+				remaining -= res[i]
+			}
+		}
+
+		open = uncapped
+	}
+
+	if total < 0 {
+		for i := range res {
+			res[i] = -res[i]
+		}
+	}
+
+	return res
+}
+
+// batteryPowerControllers returns the battery meters supporting power setpoints
+func (site *Site) batteryPowerControllers() []int {
+	var res []int
+	for i, dev := range site.batteryMeters {
+		if api.HasCap[api.BatteryPowerController](dev.Instance()) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// setBatteryTargets stores the optimizer home battery power targets
+func (site *Site) setBatteryTargets(targets []batteryTarget) {
+	site.Lock()
+	defer site.Unlock()
+	site.batteryTargets = targets
+}
+
+// batteryTarget returns the optimizer home battery power target for the given time
+func (site *Site) batteryTarget(ts time.Time) (batteryTarget, bool) {
+	site.RLock()
+	defer site.RUnlock()
+
+	for _, t := range site.batteryTargets {
+		if !ts.Before(t.Start) && ts.Before(t.End) {
+			return t, true
+		}
+	}
+
+	return batteryTarget{}, false
+}
+
+// batteryTargets returns the home battery power targets per slot from the optimizer result
+func batteryTargets(details requestDetails, dt []int, res []optimizer.BatteryResult) []batteryTarget {
+	targets := make([]batteryTarget, 0, len(details.Timestamps))
+
+	for slot, ts := range details.Timestamps {
+		target := batteryTarget{
+			Start: ts,
+			End:   ts.Add(time.Duration(dt[slot]) * time.Second),
+		}
+
+		for i, detail := range details.BatteryDetails {
+			if detail.Type != batteryTypeBattery || i >= len(res) || slot >= len(res[i].ChargingPower) || slot >= len(res[i].DischargingPower) {
+				continue
+			}
+
+			target.Power += float64(res[i].DischargingPower[slot] - res[i].ChargingPower[slot])
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
+// batteryPowerSetpoint returns the total battery power setpoint, nil if batteries control themselves.
+// Discharging is limited to house consumption not covered by pv, i.e. batteries never discharge into loadpoints.
+func (site *Site) batteryPowerSetpoint(batteryGridChargeActive bool, rate api.Rate) *float64 {
+	// battery must supply the house in off-grid operation
+	if site.GetBatteryModeExternal() != api.BatteryUnknown || site.GetOffGrid() {
+		return nil
+	}
+
+	house := site.homePower - max(0, site.pvPower)
+
+	if setpoint := site.arbitragePowerSetpoint(); setpoint != nil {
+		// exporting must not discharge into fast or planned charging vehicles
+		if *setpoint > 0 && site.dischargeControlActive(rate) {
+			return new(min(*setpoint, house))
+		}
+		return setpoint
+	}
+
+	if batteryGridChargeActive && site.BatteryGridChargePower > 0 {
+		return new(-site.BatteryGridChargePower)
+	}
+
+	if site.OptimizerBatteryControl {
+		if t, ok := site.batteryTarget(site.clock.Now()); ok {
+			if t.Power > 0 {
+				return new(min(t.Power, house))
+			}
+			return new(t.Power)
+		}
+	}
+
+	if site.dischargeControlActive(rate) {
+		return new(house)
+	}
+
+	return nil
+}
+
+// updateBatteryPower controls batteries supporting power setpoints
+func (site *Site) updateBatteryPower(batteryGridChargeActive bool, rate api.Rate) {
+	idx := site.batteryPowerControllers()
+	if len(idx) == 0 {
+		return
+	}
+
+	setpoint := site.batteryPowerSetpoint(batteryGridChargeActive, rate)
+
+	if setpoint == nil {
+		if site.batterySetpoint != nil {
+			site.log.DEBUG.Println("battery setpoint: released")
+			if err := site.releaseBatteryPower(idx); err != nil {
+				site.log.ERROR.Println("battery setpoint:", err)
+			}
+		}
+
+		site.batterySetpoint = nil
+		site.publish(keys.BatterySetpoint, nil)
+
+		return
+	}
+
+	if err := site.applyBatteryPower(idx, *setpoint); err != nil {
+		site.log.ERROR.Println("battery setpoint:", err)
+	}
+
+	site.batterySetpoint = setpoint
+	site.publish(keys.BatterySetpoint, *setpoint)
+}
+
+// applyBatteryPower distributes the power setpoint across batteries
+func (site *Site) applyBatteryPower(idx []int, total float64) error {
+	shares := make([]batteryShare, 0, len(idx))
+
+	for _, i := range idx {
+		var share batteryShare
+
+		if i < len(site.battery.Devices) && site.battery.Devices[i].Soc != nil {
+			share.soc = *site.battery.Devices[i].Soc
+		}
+
+		if m, ok := api.Cap[api.BatteryPowerLimiter](site.batteryMeters[i].Instance()); ok {
+			share.charge, share.discharge = m.GetPowerLimits()
+		}
+
+		shares = append(shares, share)
+	}
+
+	var errs error
+
+	for j, power := range distributeBatteryPower(total, shares) {
+		dev := site.batteryMeters[idx[j]]
+
+		// soc limit reached, hold instead of charging
+		if power < 0 {
+			if ok, err := site.batteryMaxSocReached(dev); err == nil && ok {
+				power = 0
+			}
+		}
+
+		m, _ := api.Cap[api.BatteryPowerController](dev.Instance())
+		if err := m.SetBatteryPower(power); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%s set power: %w", deviceTitleOrName(dev), err))
+			continue
+		}
+
+		site.log.DEBUG.Printf("set battery %s power: %.0fW", deviceTitleOrName(dev), power)
+	}
+
+	return errs
+}
+
+// releaseBatteryPower returns control to the batteries
+func (site *Site) releaseBatteryPower(idx []int) error {
+	var errs error
+
+	for _, i := range idx {
+		dev := site.batteryMeters[i]
+
+		if m, ok := api.Cap[api.BatteryController](dev.Instance()); ok {
+			if err := m.SetBatteryMode(api.BatteryNormal); err != nil && !errors.Is(err, api.ErrNotAvailable) {
+				errs = errors.Join(errs, fmt.Errorf("%s set mode: %w", deviceTitleOrName(dev), err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// batteryPowerControlled returns true if the battery is controlled by power setpoint
+func (site *Site) batteryPowerControlled(dev config.Device[api.Meter]) bool {
+	return site.batterySetpoint != nil && api.HasCap[api.BatteryPowerController](dev.Instance())
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/types"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDistributeBatteryPower(t *testing.T) {
+	for _, tc := range []struct {
+		title     string
+		total     float64
+		batteries []batteryShare
+		res       []float64
+	}{
+		{"discharge by soc", 3000, []batteryShare{{soc: 80}, {soc: 40}}, []float64{2000, 1000}},
+		{"charge by remaining capacity", -3000, []batteryShare{{soc: 80}, {soc: 40}}, []float64{-750, -2250}},
+		{"discharge limit redistributed", 3000, []batteryShare{{soc: 80, discharge: 1000}, {soc: 40}}, []float64{1000, 2000}},
+		{"charge limit redistributed", -3000, []batteryShare{{soc: 80}, {soc: 40, charge: 1500}}, []float64{-1500, -1500}},
+		{"all limited", 5000, []batteryShare{{soc: 50, discharge: 1000}, {soc: 50, discharge: 2000}}, []float64{1000, 2000}},
+		{"empty battery", 2000, []batteryShare{{soc: 0}, {soc: 50}}, []float64{0, 2000}},
+		{"full battery", -2000, []batteryShare{{soc: 100}, {soc: 50}}, []float64{0, -2000}},
+		{"hold", 0, []batteryShare{{soc: 50}, {soc: 50}}, []float64{0, 0}},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			assert.InDeltaSlice(t, tc.res, distributeBatteryPower(tc.total, tc.batteries), 1e-6)
+		})
+	}
+}
+
+func TestBatteryPowerSetpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	batCon := api.NewMockBatteryController(ctrl)
+	batPower := api.NewMockBatteryPowerController(ctrl)
+
+	bat := &struct {
+		api.Meter
+		api.BatteryController
+		api.BatteryPowerController
+	}{
+		BatteryController:      batCon,
+		BatteryPowerController: batPower,
+	}
+
+	site := &Site{
+		clock:         clock.New(),
+		log:           util.NewLogger("foo"),
+		batteryMeters: []config.Device[api.Meter]{config.NewStaticDevice(config.Named{}, api.Meter(bat))},
+		battery: types.BatteryState{
+			Devices: []types.Measurement{{Soc: new(50.0)}},
+		},
+		homePower: 1500,
+		pvPower:   500,
+	}
+
+	// no setpoint, battery controls itself
+	site.updateBatteryPower(false, api.Rate{})
+	assert.Nil(t, site.batterySetpoint)
+
+	// grid charging at configured power
+	site.BatteryGridChargePower = 2000
+	batPower.EXPECT().SetBatteryPower(-2000.0)
+	site.updateBatteryPower(true, api.Rate{})
+	assert.Equal(t, new(-2000.0), site.batterySetpoint)
+
+	// mode control skipped while setpoint active
+	site.updateBatteryMode(true, api.Rate{})
+
+	// optimizer discharge target limited to house consumption
+	site.OptimizerBatteryControl = true
+	site.setBatteryTargets([]batteryTarget{{Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Minute), Power: 3000}})
+	batPower.EXPECT().SetBatteryPower(1000.0)
+	site.updateBatteryPower(false, api.Rate{})
+
+	// released
+	site.setBatteryTargets(nil)
+	batCon.EXPECT().SetBatteryMode(api.BatteryNormal)
+	site.updateBatteryPower(false, api.Rate{})
+	assert.Nil(t, site.batterySetpoint)
+}
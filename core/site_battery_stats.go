@@ -0,0 +1,58 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/batterystats"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/types"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// updateBatteryStats accounts charged and discharged energy, cycles and degradation per battery meter
+func (site *Site) updateBatteryStats(mm []types.Measurement) {
+	now := site.clock.Now()
+
+	site.Lock()
+	for i, dev := range site.batteryMeters {
+		name := dev.Config().Name
+
+		stats, ok := site.batteryStats[name]
+		if !ok {
+			stats = new(batterystats.Stats)
+			site.batteryStats[name] = stats
+		}
+
+		var nominal float64
+		if mm[i].Capacity != nil {
+			nominal = *mm[i].Capacity
+		}
+
+		stats.Update(now, mm[i].Power, mm[i].Soc, nominal)
+	}
+
+	if err := settings.SetJson(site.settingsKey(keys.BatteryStats), site.batteryStats); err != nil {
+		site.log.ERROR.Println("battery stats:", err)
+	}
+	site.Unlock()
+
+	site.publish(keys.BatteryStats, site.GetBatteryStats())
+}
+
+// GetBatteryStats returns the cycle and degradation statistics by battery meter name
+func (site *Site) GetBatteryStats() map[string]batterystats.Stats {
+	site.RLock()
+	defer site.RUnlock()
+
+	res := make(map[string]batterystats.Stats, len(site.batteryStats))
+	for name, stats := range site.batteryStats {
+		res[name] = *stats
+	}
+
+	return res
+}
+
+// batteryWearExceedsGrid returns true if the battery wear cost exceeds the grid price, i.e. charging
+// vehicles from grid is cheaper than discharging the battery
+func (site *Site) batteryWearExceedsGrid(rate api.Rate) bool {
+	return site.BatteryCycleCost > 0 && !rate.IsZero() && rate.Value <= site.BatteryCycleCost
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/types"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatteryStats(t *testing.T) {
+	site := NewSite()
+	site.batteryMeters = []config.Device[api.Meter]{
+		config.NewStaticDevice(config.Named{Name: "bat1"}, api.Meter(nil)),
+		config.NewStaticDevice(config.Named{Name: "bat2"}, api.Meter(nil)),
+	}
+
+	site.updateBatteryStats([]types.Measurement{{Power: 1000, Soc: new(50.0)}, {Power: -1000}})
+
+	res := site.GetBatteryStats()
+	require.Len(t, res, 2)
+	assert.Equal(t, new(50.0), res["bat1"].StartSoc)
+	assert.Nil(t, res["bat2"].StartSoc)
+}
+
+func TestBatteryWearExceedsGrid(t *testing.T) {
+	site := NewSite()
+	assert.False(t, site.batteryWearExceedsGrid(api.Rate{Value: 0.1}))
+
+	site.BatteryCycleCost = 0.15
+	assert.True(t, site.batteryWearExceedsGrid(api.Rate{Value: 0.1}))
+	assert.False(t, site.batteryWearExceedsGrid(api.Rate{Value: 0.2}))
+	assert.False(t, site.batteryWearExceedsGrid(api.Rate{}))
+}
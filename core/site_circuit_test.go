@@ -1,11 +1,14 @@
 package core
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/circuit"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -50,3 +53,42 @@ func TestDimming(t *testing.T) {
 		}
 	}
 }
+
+func TestConcurrentCircuitLoadpoints(t *testing.T) {
+	Voltage = 230
+	ctrl := gomock.NewController(t)
+
+	c, err := circuit.New(util.NewLogger("circuit"), "main", 20, 0, nil, 0)
+	require.NoError(t, err)
+
+	lps := make([]*Loadpoint, 2)
+	for i := range lps {
+		charger := api.NewMockCharger(ctrl)
+		charger.EXPECT().MaxCurrent(gomock.Any()).Return(nil).AnyTimes()
+		charger.EXPECT().Enable(gomock.Any()).Return(nil).AnyTimes()
+
+		lp := NewLoadpoint(util.NewLogger("lp"), nil)
+		lp.charger = charger
+		lp.circuit = c
+		lp.minCurrent = 6
+		lp.maxCurrent = 16
+		lp.phases = 3
+		lp.wakeUpTimer = NewTimer()
+
+		lps[i] = lp
+	}
+
+	require.NoError(t, c.Update(nil))
+
+	// loadpoints are updated in parallel
+	var wg sync.WaitGroup
+	for _, lp := range lps {
+		wg.Go(func() {
+			assert.NoError(t, lp.setLimit(16))
+		})
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, []float64{16, 0}, []float64{lps[0].offeredCurrent, lps[1].offeredCurrent})
+	assert.NotEqual(t, lps[0].enabled, lps[1].enabled)
+}
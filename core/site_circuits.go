@@ -12,15 +12,16 @@ import (
 )
 
 type circuitStruct struct {
-	Title      string   `json:"title,omitempty"`
-	Icon       string   `json:"icon,omitempty"`
-	Parent     string   `json:"parent,omitempty"`
-	Power      float64  `json:"power"`
-	Current    *float64 `json:"current,omitempty"`
-	MaxPower   float64  `json:"maxPower,omitempty"`
-	MaxCurrent float64  `json:"maxCurrent,omitempty"`
-	Dimmed     bool     `json:"dimmed"`
-	Curtailed  bool     `json:"curtailed"`
+	Title      string    `json:"title,omitempty"`
+	Icon       string    `json:"icon,omitempty"`
+	Parent     string    `json:"parent,omitempty"`
+	Power      float64   `json:"power"`
+	Current    *float64  `json:"current,omitempty"`
+	Currents   []float64 `json:"currents,omitempty"`
+	MaxPower   float64   `json:"maxPower,omitempty"`
+	MaxCurrent float64   `json:"maxCurrent,omitempty"`
+	Dimmed     bool      `json:"dimmed"`
+	Curtailed  bool      `json:"curtailed"`
 }
 
 // publishCircuits returns a list of circuit titles
@@ -50,6 +51,8 @@ func (site *Site) publishCircuits() {
 
 		if instance.GetMaxCurrent() > 0 {
 			data.Current = lo.EmptyableToPtr(instance.GetMaxPhaseCurrent())
+			currents := instance.GetPhaseCurrents()
+			data.Currents = currents[:]
 		}
 
 		res[c.Config().Name] = data
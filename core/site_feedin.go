@@ -0,0 +1,195 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/hems/smartgrid"
+	"github.com/evcc-io/evcc/server/db"
+)
+
+const (
+	// feedInProbeInterval is the minimum time between probes for loadpoints waiting for surplus
+	feedInProbeInterval = 10 * time.Minute
+
+	// feedInProbeMargin is added to the loadpoints' enable delay for the probe duration
+	feedInProbeMargin = time.Minute
+)
+
+// feedInAbsorption returns the additional power loadpoints and batteries can absorb before pv needs to be limited
+func (site *Site) feedInAbsorption() float64 {
+	var res float64
+
+	for _, lp := range site.loadpoints {
+		res += lp.effectivePVHeadroom()
+	}
+
+	if site.battery.Soc < 100 {
+		var chargeLimit float64
+		for _, dev := range site.batteryMeters {
+			if m, ok := api.Cap[api.BatteryPowerLimiter](dev.Instance()); ok {
+				charge, _ := m.GetPowerLimits()
+				chargeLimit += charge
+			}
+		}
+
+		res += max(0, chargeLimit-max(0, -site.battery.Power))
+	}
+
+	return res
+}
+
+// feedInStartPower returns the power loadpoints waiting for surplus require to start charging and their longest enable delay
+func (site *Site) feedInStartPower() (float64, time.Duration) {
+	var (
+		res   float64
+		delay time.Duration
+	)
+
+	for _, lp := range site.loadpoints {
+		power, d := lp.effectivePVStartPower()
+		res += power
+		delay = max(delay, d)
+	}
+
+	return res, delay
+}
+
+// feedInLimiters returns the pv meter indexes supporting active power limitation and their rated power
+func (site *Site) feedInLimiters() ([]int, float64) {
+	var (
+		res   []int
+		rated float64
+	)
+
+	for i, dev := range site.pvMeters {
+		if !api.HasCap[api.PowerLimiter](dev.Instance()) {
+			continue
+		}
+
+		m, ok := api.Cap[api.MaxACPowerGetter](dev.Instance())
+		if !ok || m.MaxACPower() <= 0 {
+			site.log.DEBUG.Printf("feed-in limit: pv %d requires max ac power", i+1)
+			continue
+		}
+
+		res = append(res, i)
+		rated += m.MaxACPower()
+	}
+
+	return res, rated
+}
+
+// limitFeedIn keeps grid export at or below the limit by first shifting load into
+// loadpoints and batteries and then limiting the active power of pv inverters
+func (site *Site) limitFeedIn(limit float64) {
+	limiters, rated := site.feedInLimiters()
+	if len(limiters) == 0 {
+		return
+	}
+
+	// inverters may still be limited from a previous run
+	if !site.pvLimitApplied {
+		if err := site.setPVLimit(limiters, 100); err != nil {
+			site.log.ERROR.Println("feed-in limit:", err)
+			return
+		}
+
+		site.pvLimit = 100
+		site.pvLimitApplied = true
+	}
+
+	var limitable float64
+	for _, i := range limiters {
+		if i < len(site.pvPowers) {
+			limitable += max(0, site.pvPowers[i])
+		}
+	}
+
+	export := max(0, -site.gridPower)
+	absorption := site.feedInAbsorption()
+
+	// excess export after loadpoints and batteries have absorbed their share, negative if below limit or importing
+	excess := -site.gridPower - limit - absorption
+
+	// export held at the limit hides surplus from loadpoints that have not started charging.
+	// Periodically raise the limit by their start power for long enough to enable them.
+	if startPower, delay := site.feedInStartPower(); startPower > 0 && (site.pvLimit < 100 || excess > 0) {
+		now := site.clock.Now()
+		if now.Sub(site.feedInProbe) >= feedInProbeInterval {
+			site.log.DEBUG.Printf("feed-in limit: probing %.0fW for waiting loadpoints", startPower)
+			site.feedInProbe = now
+		}
+
+		if now.Sub(site.feedInProbe) < delay+feedInProbeMargin {
+			absorption += startPower
+			excess -= startPower
+		}
+	}
+
+	if site.pvLimit >= 100 && excess <= 0 {
+		return
+	}
+
+	target := max(0, limitable-excess)
+	pct := math.Round(min(100, 100*target/rated))
+
+	site.log.DEBUG.Printf("feed-in limit: %.0fW export, %.0fW limit, %.0fW absorbable, pv limit %.0f%% (%.0fW)", export, limit, absorption, pct, target)
+
+	if pct != site.pvLimit {
+		if err := site.setPVLimit(limiters, pct); err != nil {
+			site.log.ERROR.Println("feed-in limit:", err)
+			return
+		}
+
+		if pct < 100 && site.pvLimit >= 100 {
+			site.log.INFO.Printf("feed-in limit: limiting pv to %.0f%%", pct)
+		} else if pct >= 100 {
+			site.log.INFO.Println("feed-in limit: pv unlimited")
+		}
+
+		site.pvLimit = pct
+	}
+
+	site.publish(keys.PvLimit, site.pvLimit)
+
+	if db.Instance == nil {
+		return
+	}
+
+	if err := smartgrid.UpdateSession(&site.feedInSessionID, smartgrid.Curtail, export, limit, site.pvLimit < 100); err != nil {
+		site.log.ERROR.Println("smartgrid session:", err)
+	}
+}
+
+// releasePVLimit removes the active power limit of all limitable pv inverters
+func (site *Site) releasePVLimit() {
+	limiters, _ := site.feedInLimiters()
+	if len(limiters) == 0 {
+		return
+	}
+
+	if err := site.setPVLimit(limiters, 100); err != nil {
+		site.log.ERROR.Println("feed-in limit:", err)
+	}
+}
+
+// setPVLimit sets the active power limit of all limitable pv inverters
+func (site *Site) setPVLimit(limiters []int, pct float64) error {
+	var errs error
+
+	for _, i := range limiters {
+		dev := site.pvMeters[i]
+
+		m, _ := api.Cap[api.PowerLimiter](dev.Instance())
+		if err := m.LimitPower(pct); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%s limit power: %w", dev.Config().Name, err))
+		}
+	}
+
+	return errs
+}
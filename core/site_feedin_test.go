@@ -0,0 +1,114 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type limitablePV struct {
+	api.Meter
+	limit float64
+}
+
+func (m *limitablePV) MaxACPower() float64 {
+	return 10e3
+}
+
+func (m *limitablePV) LimitPower(limit float64) error {
+	m.limit = limit
+	return nil
+}
+
+func TestLimitFeedIn(t *testing.T) {
+	pv := &limitablePV{limit: 100}
+
+	site := &Site{
+		clock:    clock.New(),
+		log:      util.NewLogger("foo"),
+		pvMeters: []config.Device[api.Meter]{config.NewStaticDevice(config.Named{}, api.Meter(pv))},
+		pvLimit:  100,
+	}
+
+	for _, tc := range []struct {
+		name            string
+		pv, grid, limit float64
+		expected        float64
+	}{
+		{"below limit", 5e3, -500, 1e3, 100},
+		{"export above limit", 5e3, -3e3, 1e3, 30},
+		{"at limit", 3e3, -1e3, 1e3, 30},
+		{"import relaxes limit", 3e3, 1e3, 1e3, 50},
+		{"zero export", 5e3, -1e3, 0, 40},
+		{"unlimited", 9.8e3, -500, 1e3, 100},
+	} {
+		t.Log(tc.name)
+
+		site.pvPowers = []float64{tc.pv}
+		site.gridPower = tc.grid
+		site.limitFeedIn(tc.limit)
+
+		assert.Equal(t, tc.expected, pv.limit, tc.name)
+		assert.Equal(t, tc.expected, site.pvLimit, tc.name)
+	}
+}
+
+func TestLimitFeedInProbe(t *testing.T) {
+	Voltage = 230
+
+	pv := &limitablePV{limit: 30}
+
+	lp := NewLoadpoint(util.NewLogger("foo"), nil)
+	lp.status = api.StatusB
+	lp.mode = api.ModePV
+	lp.minCurrent = 6
+	lp.phases = 1
+	lp.phasesConfigured = 1
+	lp.Enable.Delay = time.Minute
+
+	site := &Site{
+		clock:      clock.New(),
+		log:        util.NewLogger("foo"),
+		pvMeters:   []config.Device[api.Meter]{config.NewStaticDevice(config.Named{}, api.Meter(pv))},
+		loadpoints: []*Loadpoint{lp},
+		pvLimit:    100,
+	}
+
+	// inverter limited from previous run is released
+	site.pvPowers = []float64{3e3}
+	site.gridPower = 0
+	site.limitFeedIn(1e3)
+	assert.Equal(t, 100.0, pv.limit)
+
+	// export above the limit, waiting loadpoint is offered its start power
+	site.pvPowers = []float64{5e3}
+	site.gridPower = -3e3
+	site.limitFeedIn(1e3)
+	assert.Equal(t, 44.0, pv.limit) // 3kW + 1.38kW
+
+	site.pvPowers = []float64{4.38e3}
+	site.gridPower = -2.38e3
+	site.limitFeedIn(1e3)
+	assert.Equal(t, 44.0, pv.limit, "probe held during enable delay")
+
+	// probe expired
+	site.feedInProbe = time.Now().Add(-lp.Enable.Delay - feedInProbeMargin)
+	site.limitFeedIn(1e3)
+	assert.Equal(t, 30.0, pv.limit)
+
+	// probed again after interval
+	site.pvPowers = []float64{3e3}
+	site.gridPower = -1e3
+	site.feedInProbe = time.Now().Add(-feedInProbeInterval)
+	site.limitFeedIn(1e3)
+	assert.Equal(t, 44.0, pv.limit)
+
+	// released on shutdown
+	site.releasePVLimit()
+	assert.Equal(t, 100.0, pv.limit)
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"slices"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/messenger"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+const (
+	evOffGrid = "offgrid" // grid power lost
+	evOnGrid  = "ongrid"  // grid power returned
+)
+
+// pushEvent sends push messages to clients
+func (site *Site) pushEvent(event string) {
+	if site.pushChan == nil {
+		return
+	}
+	site.pushChan <- messenger.Event{Event: event, Site: site.name}
+}
+
+// meterOffGrid returns true if the meter supports off-grid detection and signals off-grid operation
+func (site *Site) meterOffGrid(name string, meter api.Meter) bool {
+	m, ok := api.Cap[api.OffGridDetector](meter)
+	if !ok {
+		return false
+	}
+
+	offGrid, err := m.OffGrid()
+	if err != nil {
+		site.log.ERROR.Printf("off-grid %s: %v", name, err)
+	}
+
+	return offGrid
+}
+
+// detectOffGrid returns true if the off-grid signal or any site meter indicates off-grid operation
+func (site *Site) detectOffGrid() bool {
+	if site.offGridG != nil {
+		offGrid, err := site.offGridG()
+		if err == nil {
+			return offGrid
+		}
+		site.log.ERROR.Println("off-grid:", err)
+	}
+
+	if site.gridMeter != nil && site.meterOffGrid(site.Meters.GridMeterRef, site.gridMeter) {
+		return true
+	}
+
+	return slices.ContainsFunc(slices.Concat(site.pvMeters, site.batteryMeters), func(dev config.Device[api.Meter]) bool {
+		return site.meterOffGrid(dev.Config().Name, dev.Instance())
+	})
+}
+
+// updateOffGrid updates and publishes the off-grid state, notifying on changes.
+// Normal operation resumes automatically once grid power returns.
+func (site *Site) updateOffGrid() {
+	offGrid := site.detectOffGrid()
+
+	site.Lock()
+	changed := offGrid != site.offGrid
+	site.offGrid = offGrid
+	site.Unlock()
+
+	site.publish(keys.OffGrid, offGrid)
+
+	if !changed {
+		return
+	}
+
+	if offGrid {
+		site.log.WARN.Println("off-grid: grid power lost, loadpoints limited to pv surplus")
+		site.pushEvent(evOffGrid)
+	} else {
+		site.log.INFO.Println("off-grid: grid power returned, resuming normal operation")
+		site.pushEvent(evOnGrid)
+	}
+}
+
+// GetOffGrid returns true if the site is in off-grid operation
+func (site *Site) GetOffGrid() bool {
+	site.RLock()
+	defer site.RUnlock()
+	return site.offGrid
+}
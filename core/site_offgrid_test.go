@@ -0,0 +1,57 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/messenger"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type offGridMeter struct {
+	offGrid bool
+}
+
+func (m *offGridMeter) CurrentPower() (float64, error) {
+	return 0, nil
+}
+
+func (m *offGridMeter) OffGrid() (bool, error) {
+	return m.offGrid, nil
+}
+
+func TestOffGrid(t *testing.T) {
+	pushChan := make(chan messenger.Event, 1)
+
+	battery := new(offGridMeter)
+
+	site := NewSite()
+	site.pushChan = pushChan
+	site.batteryMeters = []config.Device[api.Meter]{
+		config.NewStaticDevice(config.Named{Name: "battery"}, api.Meter(battery)),
+	}
+
+	site.updateOffGrid()
+	assert.False(t, site.GetOffGrid())
+	assert.Empty(t, pushChan)
+
+	// meter capability
+	battery.offGrid = true
+	site.updateOffGrid()
+	assert.True(t, site.GetOffGrid())
+	require.Len(t, pushChan, 1)
+	assert.Equal(t, evOffGrid, (<-pushChan).Event)
+
+	// no battery control off-grid
+	site.BatteryGridChargePower = 5000
+	assert.Nil(t, site.batteryPowerSetpoint(true, api.Rate{}))
+
+	// signal takes precedence
+	site.offGridG = func() (bool, error) { return false, nil }
+	site.updateOffGrid()
+	assert.False(t, site.GetOffGrid())
+	require.Len(t, pushChan, 1)
+	assert.Equal(t, evOnGrid, (<-pushChan).Event)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/metrics"
 	"github.com/evcc-io/evcc/core/types"
+	"github.com/evcc-io/evcc/core/vehicle"
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/evcc-io/evcc/util/request"
@@ -288,6 +289,8 @@ func (site *Site) optimizerUpdate(battery []types.Measurement) error {
 
 	site.publish("evopt-batteries", batteries)
 
+	site.setBatteryTargets(batteryTargets(details, dt, resp.JSON200.Batteries))
+
 	site.battery.Forecast = site.addBatteryForecastTotals(req.Batteries, resp.JSON200.Batteries)
 
 	site.publish(keys.Battery, site.battery)
@@ -418,6 +421,15 @@ func (site *Site) loadpointRequest(lp loadpoint.API, minLen int, firstSlotDurati
 		// add smartcost limit and plan goal, if configured
 		demand = applySmartCostLimit(lp, nil, grid, minLen)
 		site.applyPlanGoal(lp, &bat, minLen)
+
+	case api.ModeV2H:
+		// vehicle acts as home battery down to min soc
+		bat.CMax = 0
+		if minSoc := vehicle.Settings(site.log, v).GetMinSoc(); minSoc > 0 {
+			bat.DMax = float32(lp.EffectiveMaxPower())
+			bat.SMin = min(bat.SInitial, float32(v.Capacity()*float64(minSoc)*10)) // Wh
+		}
+		site.applyPlanGoal(lp, &bat, minLen)
 	}
 
 	if demand != nil {
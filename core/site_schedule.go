@@ -0,0 +1,45 @@
+package core
+
+import (
+	"strconv"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/schedule"
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// scheduleOwner returns the site settings that can be scheduled and the schedule's persistence
+func (site *Site) scheduleOwner() schedule.Owner {
+	return schedule.Owner{
+		Log: site.log,
+		Settings: map[string]schedule.Setting{
+			keys.BufferSoc:               schedule.Func(schedule.ParseFloat, site.SetBufferSoc),
+			keys.BufferStartSoc:          schedule.Func(schedule.ParseFloat, site.SetBufferStartSoc),
+			keys.PrioritySoc:             schedule.Func(schedule.ParseFloat, site.SetPrioritySoc),
+			keys.ResidualPower:           schedule.Func(schedule.ParseFloat, site.SetResidualPower),
+			keys.BatteryDischargeControl: schedule.Func(strconv.ParseBool, site.SetBatteryDischargeControl),
+			keys.BatteryGridChargeLimit:  schedule.Func(schedule.ParseFloatPtr, site.SetBatteryGridChargeLimit),
+			keys.BatteryModeExternal:     schedule.Func(api.BatteryModeString, site.SetBatteryModeExternal),
+		},
+		Save: func(changes []api.ScheduledChange) error {
+			return settings.SetJson(site.settingsKey(keys.Schedule), changes)
+		},
+		Publish: site.publish,
+	}
+}
+
+// GetSchedule returns the scheduled setting changes
+func (site *Site) GetSchedule() []api.ScheduledChange {
+	return site.schedule.Get()
+}
+
+// SetSchedule sets the scheduled setting changes
+func (site *Site) SetSchedule(changes []api.ScheduledChange) error {
+	return site.schedule.Set(site.scheduleOwner(), changes, site.clock.Now())
+}
+
+// applySchedule applies scheduled changes that became due since the last update
+func (site *Site) applySchedule() {
+	site.schedule.Apply(site.scheduleOwner(), site.clock.Now())
+}
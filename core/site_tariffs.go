@@ -128,11 +128,11 @@ func (site *Site) solarDetails(solar api.Rates) solarDetails {
 
 	last := solar[len(solar)-1].Start
 
-	bod := now.BeginningOfDay()
+	bod := now.With(site.clock.Now()).BeginningOfDay()
 	eod := bod.AddDate(0, 0, 1)
 	eot := eod.AddDate(0, 0, 1)
 
-	remainingToday := solarEnergy(solar, time.Now(), eod)
+	remainingToday := solarEnergy(solar, site.clock.Now(), eod)
 	tomorrow := solarEnergy(solar, eod, eot)
 	dayAfterTomorrow := solarEnergy(solar, eot, eot.AddDate(0, 0, 1))
 
@@ -150,13 +150,13 @@ func (site *Site) solarDetails(solar api.Rates) solarDetails {
 	}
 
 	// accumulate forecasted energy since last update
-	energy := solarEnergy(solar, site.fcstEnergy.updated, time.Now()) / 1e3
+	energy := solarEnergy(solar, site.fcstEnergy.updated, site.clock.Now()) / 1e3
 	site.log.DEBUG.Printf("solar forecast: accumulated %.3fWh from %v to %v",
-		energy, site.fcstEnergy.updated.Truncate(time.Second), time.Now().Truncate(time.Second),
+		energy, site.fcstEnergy.updated.Truncate(time.Second), site.clock.Now().Truncate(time.Second),
 	)
 
 	site.fcstEnergy.AddEnergy(energy)
-	settings.SetFloat(keys.SolarAccForecast, site.fcstEnergy.Accumulated)
+	settings.SetFloat(site.settingsKey(keys.SolarAccForecast), site.fcstEnergy.Accumulated)
 
 	produced := lo.SumBy(slices.Collect(maps.Values(site.pvEnergy)), func(v *meterEnergy) float64 {
 		return v.AccumulatedEnergy()
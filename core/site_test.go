@@ -6,6 +6,7 @@ import (
 
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/types"
 	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/util"
@@ -193,3 +194,13 @@ func TestUpdateHomeConsumption(t *testing.T) {
 	s.updateHomeConsumption(1e3)
 	require.Equal(t, 0.0, s.householdEnergy.AccumulatedEnergy()) // accumulator reset after 15 minutes
 }
+
+func TestSiteSettingsKey(t *testing.T) {
+	site := NewSite()
+	assert.Equal(t, "bufferSoc", site.settingsKey(keys.BufferSoc))
+
+	site, err := NewNamedSiteFromConfig("garage", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "garage", site.Name())
+	assert.Equal(t, "sites.garage.bufferSoc", site.settingsKey(keys.BufferSoc))
+}
@@ -18,19 +18,20 @@ type planStruct struct {
 }
 
 type vehicleStruct struct {
-	Title          string              `json:"title"`
-	Icon           string              `json:"icon,omitempty"`
-	Capacity       float64             `json:"capacity,omitempty"`
-	Phases         int                 `json:"phases,omitempty"`
-	MinSoc         int                 `json:"minSoc,omitempty"`
-	LimitSoc       int                 `json:"limitSoc,omitempty"`
-	MinCurrent     float64             `json:"minCurrent,omitempty"`
-	MaxCurrent     float64             `json:"maxCurrent,omitempty"`
-	Priority       int                 `json:"priority,omitempty"`
-	Features       []string            `json:"features,omitempty"`
-	Plan           *planStruct         `json:"plan,omitempty"`
-	RepeatingPlans []api.RepeatingPlan `json:"repeatingPlans"`
-	PlanStrategy   api.PlanStrategy    `json:"planStrategy"`
+	Title              string                 `json:"title"`
+	Icon               string                 `json:"icon,omitempty"`
+	Capacity           float64                `json:"capacity,omitempty"`
+	Phases             int                    `json:"phases,omitempty"`
+	MinSoc             int                    `json:"minSoc,omitempty"`
+	LimitSoc           int                    `json:"limitSoc,omitempty"`
+	MinCurrent         float64                `json:"minCurrent,omitempty"`
+	MaxCurrent         float64                `json:"maxCurrent,omitempty"`
+	Priority           int                    `json:"priority,omitempty"`
+	Features           []string               `json:"features,omitempty"`
+	Plan               *planStruct            `json:"plan,omitempty"`
+	RepeatingPlans     []api.RepeatingPlan    `json:"repeatingPlans"`
+	PlanStrategy       api.PlanStrategy       `json:"planStrategy"`
+	DepartureGuarantee api.DepartureGuarantee `json:"departureGuarantee"`
 }
 
 // publishVehicles returns a list of vehicle titles
@@ -55,19 +56,20 @@ func (site *Site) publishVehicles() {
 		}
 
 		res[v.Name()] = vehicleStruct{
-			Title:          instance.GetTitle(),
-			Icon:           instance.Icon(),
-			Capacity:       instance.Capacity(),
-			Phases:         instance.Phases(),
-			MinSoc:         v.GetMinSoc(),
-			LimitSoc:       v.GetLimitSoc(),
-			MinCurrent:     ac.MinCurrent,
-			MaxCurrent:     ac.MaxCurrent,
-			Priority:       ac.Priority,
-			Features:       lo.Map(instance.Features(), func(f api.Feature, _ int) string { return f.String() }),
-			Plan:           plan,
-			RepeatingPlans: v.GetRepeatingPlans(),
-			PlanStrategy:   v.GetPlanStrategy(),
+			Title:              instance.GetTitle(),
+			Icon:               instance.Icon(),
+			Capacity:           instance.Capacity(),
+			Phases:             instance.Phases(),
+			MinSoc:             v.GetMinSoc(),
+			LimitSoc:           v.GetLimitSoc(),
+			MinCurrent:         ac.MinCurrent,
+			MaxCurrent:         ac.MaxCurrent,
+			Priority:           ac.Priority,
+			Features:           lo.Map(instance.Features(), func(f api.Feature, _ int) string { return f.String() }),
+			Plan:               plan,
+			RepeatingPlans:     v.GetRepeatingPlans(),
+			PlanStrategy:       v.GetPlanStrategy(),
+			DepartureGuarantee: v.GetDepartureGuarantee(),
 		}
 
 		// publish effective plan strategy immediately for soc-based planning
@@ -79,22 +81,6 @@ func (site *Site) publishVehicles() {
 	site.publish(keys.Vehicles, res)
 }
 
-// updateVehicles adds or removes a vehicle asynchronously
-func (site *Site) updateVehicles(op config.Operation, dev config.Device[api.Vehicle]) {
-	vehicle := dev.Instance()
-
-	switch op {
-	case config.OpAdd:
-		site.coordinator.Add(vehicle)
-
-	case config.OpDelete:
-		site.coordinator.Delete(vehicle)
-	}
-
-	// TODO remove vehicle from mqtt
-	site.publishVehicles()
-}
-
 var _ site.Vehicles = (*vehicles)(nil)
 
 type vehicles struct {
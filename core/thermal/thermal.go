@@ -0,0 +1,195 @@
+package thermal
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/plugin"
+)
+
+// waterCapacity is the specific heat capacity of water in kWh/(l*K)
+const waterCapacity = 1.163e-3
+
+// CopPoint is the coefficient of performance at the given outside temperature
+type CopPoint struct {
+	Temp float64 `json:"temp"` // outside temperature in °C
+	Cop  float64 `json:"cop"`
+}
+
+// Config defines the thermal storage of a heating device
+type Config struct {
+	Volume   float64        `json:"volume"`   // water tank volume in l
+	Capacity float64        `json:"capacity"` // thermal mass in kWh/K, alternative to volume
+	Loss     float64        `json:"loss"`     // heat loss in W/K
+	Ambient  *float64       `json:"ambient"`  // ambient temperature of indoor tanks in °C, outside temperature if empty
+	MinTemp  float64        `json:"minTemp"`  // lower comfort bound in °C
+	MaxTemp  float64        `json:"maxTemp"`  // upper comfort bound in °C
+	Cop      []CopPoint     `json:"cop"`      // coefficient of performance by outside temperature, 1 if empty
+	Outside  *plugin.Config `json:"outside"`  // outside temperature in °C
+	Forecast string         `json:"forecast"` // tariff providing the outside temperature forecast in °C
+}
+
+// Configured returns true if a thermal model is configured
+func (c Config) Configured() bool {
+	return c.Volume > 0 || c.Capacity > 0
+}
+
+// Slot is a planning slot of the thermal model
+type Slot struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Price   float64   `json:"price"`   // price of electric energy
+	Outside float64   `json:"outside"` // outside temperature in °C
+	Heat    bool      `json:"heat"`    // heating planned
+	Temp    float64   `json:"temp"`    // predicted temperature at end of slot in °C
+}
+
+// Model predicts the temperature of a thermal storage
+type Model struct {
+	config   Config
+	capacity float64 // kWh/K
+}
+
+// New creates a thermal model
+func New(config Config) (*Model, error) {
+	capacity := config.Capacity
+	if capacity == 0 {
+		capacity = config.Volume * waterCapacity
+	}
+
+	if capacity <= 0 {
+		return nil, errors.New("missing volume or capacity")
+	}
+
+	if config.MaxTemp <= config.MinTemp {
+		return nil, errors.New("maxTemp must be above minTemp")
+	}
+
+	if config.Ambient == nil && config.Outside == nil && config.Forecast == "" {
+		return nil, errors.New("missing ambient or outside temperature")
+	}
+
+	cop := slices.Clone(config.Cop)
+	slices.SortFunc(cop, func(a, b CopPoint) int {
+		return cmp.Compare(a.Temp, b.Temp)
+	})
+	config.Cop = cop
+
+	return &Model{
+		config:   config,
+		capacity: capacity,
+	}, nil
+}
+
+// MinTemp returns the lower comfort bound
+func (m *Model) MinTemp() float64 {
+	return m.config.MinTemp
+}
+
+// MaxTemp returns the upper comfort bound
+func (m *Model) MaxTemp() float64 {
+	return m.config.MaxTemp
+}
+
+// Ambient returns the ambient temperature for the given outside temperature
+func (m *Model) Ambient(outside float64) float64 {
+	if m.config.Ambient != nil {
+		return *m.config.Ambient
+	}
+	return outside
+}
+
+// Cop returns the coefficient of performance interpolated for the given outside temperature
+func (m *Model) Cop(outside float64) float64 {
+	cop := m.config.Cop
+
+	switch {
+	case len(cop) == 0:
+		return 1
+	case outside <= cop[0].Temp:
+		return cop[0].Cop
+	case outside >= cop[len(cop)-1].Temp:
+		return cop[len(cop)-1].Cop
+	}
+
+	i := slices.IndexFunc(cop, func(p CopPoint) bool { return p.Temp > outside })
+	lo, hi := cop[i-1], cop[i]
+
+	return lo.Cop + (hi.Cop-lo.Cop)*(outside-lo.Temp)/(hi.Temp-lo.Temp)
+}
+
+// Predict returns the temperature after heating with the given electric power in W for the given duration.
+// Heating stops once the upper comfort bound is reached.
+func (m *Model) Predict(temp, power, outside float64, d time.Duration) float64 {
+	heat := power / 1e3 * m.Cop(outside)
+	loss := m.config.Loss / 1e3 * (temp - m.Ambient(outside))
+
+	temp += (heat - loss) * d.Hours() / m.capacity
+
+	if power > 0 && temp > m.config.MaxTemp {
+		temp = m.config.MaxTemp
+	}
+
+	return temp
+}
+
+// simulate predicts the temperatures at the end of each slot
+func (m *Model) simulate(temp, power float64, slots []Slot) {
+	for i := range slots {
+		var p float64
+		if slots[i].Heat {
+			p = power
+		}
+
+		temp = m.Predict(temp, p, slots[i].Outside, slots[i].End.Sub(slots[i].Start))
+		slots[i].Temp = temp
+	}
+}
+
+// Plan selects the cheapest heating slots keeping the temperature above the lower comfort bound.
+// Heating with electric power in W is shifted to slots with the lowest cost of heat, i.e. price per cop,
+// as long as the heat can be stored below the upper comfort bound.
+func (m *Model) Plan(temp, power float64, slots []Slot) []Slot {
+	res := slices.Clone(slots)
+
+	for {
+		m.simulate(temp, power, res)
+
+		violation := slices.IndexFunc(res, func(s Slot) bool {
+			return s.Temp < m.config.MinTemp
+		})
+		if violation < 0 {
+			break
+		}
+
+		best := -1
+		var cost float64
+
+		for i := range res[:violation+1] {
+			start := temp
+			if i > 0 {
+				start = res[i-1].Temp
+			}
+
+			if res[i].Heat || start >= m.config.MaxTemp {
+				continue
+			}
+
+			// prefer later slots on equal cost to reduce losses
+			if c := res[i].Price / m.Cop(res[i].Outside); best < 0 || c <= cost {
+				best, cost = i, c
+			}
+		}
+
+		// comfort bound cannot be kept
+		if best < 0 {
+			break
+		}
+
+		res[best].Heat = true
+	}
+
+	return res
+}
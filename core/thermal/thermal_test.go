@@ -0,0 +1,82 @@
+package thermal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{MinTemp: 45, MaxTemp: 60, Ambient: new(20.0)})
+	assert.Error(t, err)
+
+	_, err = New(Config{Volume: 300, MinTemp: 60, MaxTemp: 45, Ambient: new(20.0)})
+	assert.Error(t, err)
+
+	_, err = New(Config{Volume: 300, MinTemp: 45, MaxTemp: 60})
+	assert.Error(t, err)
+
+	m, err := New(Config{Volume: 300, MinTemp: 45, MaxTemp: 60, Ambient: new(20.0)})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.3489, m.capacity, 1e-9)
+}
+
+func TestCop(t *testing.T) {
+	m, err := New(Config{Capacity: 1, MinTemp: 20, MaxTemp: 23, Cop: []CopPoint{
+		{Temp: 7, Cop: 4},
+		{Temp: -7, Cop: 2.5},
+		{Temp: 2, Cop: 3.5},
+	}, Forecast: "weather"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2.5, m.Cop(-10))
+	assert.Equal(t, 3.0, m.Cop(-2.5))
+	assert.Equal(t, 3.5, m.Cop(2))
+	assert.Equal(t, 4.0, m.Cop(20))
+}
+
+func TestPredict(t *testing.T) {
+	m, err := New(Config{Capacity: 1, Loss: 100, MinTemp: 20, MaxTemp: 23, Cop: []CopPoint{{Temp: 0, Cop: 3}}, Forecast: "weather"})
+	require.NoError(t, err)
+
+	// 100W/K * 20K = 2kW loss
+	assert.InDelta(t, 18, m.Predict(20, 0, 0, time.Hour), 1e-9)
+
+	// 1kW * cop 3 = 3kW heat
+	assert.InDelta(t, 21, m.Predict(20, 1000, 0, time.Hour), 1e-9)
+
+	// heating stops at upper bound
+	assert.InDelta(t, 23, m.Predict(22, 5000, 0, time.Hour), 1e-9)
+}
+
+func TestPlan(t *testing.T) {
+	m, err := New(Config{Capacity: 1, Loss: 50, Ambient: new(20.0), MinTemp: 50, MaxTemp: 60})
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var slots []Slot
+	for i, price := range []float64{0.3, 0.1, 0.3, 0.3, 0.3, 0.3} {
+		slots = append(slots, Slot{
+			Start: now.Add(time.Duration(i) * time.Hour),
+			End:   now.Add(time.Duration(i+1) * time.Hour),
+			Price: price,
+		})
+	}
+
+	// 55°C loses 1.75K in the first hour, dropping below 50°C in the 4th hour
+	res := m.Plan(55, 4000, slots)
+	require.Len(t, res, len(slots))
+	assert.False(t, res[0].Heat)
+	assert.True(t, res[1].Heat, "cheap slot")
+	assert.Greater(t, res[1].Temp, res[0].Temp)
+	for _, s := range res {
+		assert.GreaterOrEqual(t, s.Temp, 50.0)
+	}
+
+	// below lower bound heats immediately
+	res = m.Plan(48, 4000, slots)
+	assert.True(t, res[0].Heat)
+}
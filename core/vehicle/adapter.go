@@ -1,11 +1,15 @@
 package vehicle
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/fingerprint"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util"
@@ -13,11 +17,46 @@ import (
 
 var _ API = (*adapter)(nil)
 
+var (
+	mu               sync.Mutex
+	publishers       []func()
+	planLockClearers []func()
+)
+
+// OnPublish registers a site's callback for publishing vehicle updates
+func OnPublish(fn func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	publishers = append(publishers, fn)
+}
+
+// OnClearPlanLocks registers a site's callback for clearing locked plan goals
+func OnClearPlanLocks(fn func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	planLockClearers = append(planLockClearers, fn)
+}
+
+// callbacks returns a copy of the registered callbacks
+func callbacks(fns *[]func()) []func() {
+	mu.Lock()
+	defer mu.Unlock()
+	return slices.Clone(*fns)
+}
+
 // Publish publishes vehicle updates at site level
-var Publish func()
+func Publish() {
+	for _, fn := range callbacks(&publishers) {
+		fn()
+	}
+}
 
 // ClearPlanLocks clears locked plan goals across all loadpoints
-var ClearPlanLocks func()
+func ClearPlanLocks() {
+	for _, fn := range callbacks(&planLockClearers) {
+		fn()
+	}
+}
 
 type adapter struct {
 	log         *util.Logger
@@ -30,15 +69,11 @@ func (v *adapter) key() string {
 }
 
 func (v *adapter) publish() {
-	if Publish != nil {
-		Publish()
-	}
+	Publish()
 }
 
 func (v *adapter) clearPlanLocks() {
-	if ClearPlanLocks != nil {
-		ClearPlanLocks()
-	}
+	ClearPlanLocks()
 }
 
 func (v *adapter) Instance() api.Vehicle {
@@ -156,6 +191,46 @@ func (v *adapter) GetRepeatingPlans() []api.RepeatingPlan {
 	return plans
 }
 
+// GetDepartureGuarantee returns the departure guarantee
+func (v *adapter) GetDepartureGuarantee() api.DepartureGuarantee {
+	var res api.DepartureGuarantee
+	if err := settings.Json(v.key()+keys.DepartureGuarantee, &res); err != nil {
+		return api.DepartureGuarantee{}
+	}
+	return res
+}
+
+// SetDepartureGuarantee sets the departure guarantee
+func (v *adapter) SetDepartureGuarantee(guarantee api.DepartureGuarantee) error {
+	for _, day := range guarantee.Weekdays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("weekday out of range: %v", day)
+		}
+	}
+	if _, err := time.LoadLocation(cmp.Or(guarantee.Tz, "Local")); err != nil {
+		return fmt.Errorf("invalid timezone: %v", err)
+	}
+	if _, err := time.Parse("15:04", guarantee.Time); err != nil {
+		return fmt.Errorf("invalid time: %v", err)
+	}
+	if guarantee.Soc < 0 || guarantee.Soc > 100 {
+		return fmt.Errorf("soc out of range: %v", guarantee.Soc)
+	}
+	if guarantee.Range < 0 {
+		return fmt.Errorf("range out of range: %v", guarantee.Range)
+	}
+
+	if err := settings.SetJson(v.key()+keys.DepartureGuarantee, guarantee); err != nil {
+		return err
+	}
+
+	v.log.DEBUG.Printf("update departure guarantee for %s to: %v", v.name, guarantee)
+
+	v.publish()
+
+	return nil
+}
+
 func (v *adapter) GetPlanStrategy() api.PlanStrategy {
 	var strategy api.PlanStrategy
 	if err := settings.Json(v.key()+keys.PlanStrategy, &strategy); err != nil {
@@ -173,3 +248,17 @@ func (v *adapter) SetPlanStrategy(planStrategy api.PlanStrategy) error {
 
 	return nil
 }
+
+// GetFingerprint returns the learned charge behavior
+func (v *adapter) GetFingerprint() fingerprint.Profile {
+	var res fingerprint.Profile
+	if err := settings.Json(v.key()+keys.Fingerprint, &res); err != nil {
+		return fingerprint.Profile{}
+	}
+	return res
+}
+
+// SetFingerprint stores the learned charge behavior
+func (v *adapter) SetFingerprint(profile fingerprint.Profile) error {
+	return settings.SetJson(v.key()+keys.Fingerprint, profile)
+}
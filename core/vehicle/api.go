@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/fingerprint"
 )
 
 //go:generate go tool mockgen -package vehicle -destination mock.go -mock_names API=MockAPI github.com/evcc-io/evcc/core/vehicle API
@@ -48,11 +49,21 @@ type API interface {
 	// SetRepeatingPlans stores every repeating plan
 	SetRepeatingPlans([]api.RepeatingPlan) error
 
+	// GetDepartureGuarantee returns the departure guarantee
+	GetDepartureGuarantee() api.DepartureGuarantee
+	// SetDepartureGuarantee sets the departure guarantee
+	SetDepartureGuarantee(api.DepartureGuarantee) error
+
 	// GetPlanStrategy returns the plan strategy
 	GetPlanStrategy() api.PlanStrategy
 	// SetPlanStrategy sets the plan strategy
 	SetPlanStrategy(api.PlanStrategy) error
 
+	// GetFingerprint returns the charge behavior learned from past sessions
+	GetFingerprint() fingerprint.Profile
+	// SetFingerprint stores the charge behavior learned from past sessions
+	SetFingerprint(fingerprint.Profile) error
+
 	// // GetMinCurrent returns the min charging current
 	// GetMinCurrent() float64
 	// // SetMinCurrent sets the min charging current
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/fingerprint"
 )
 
 var _ API = (*dummy)(nil)
@@ -57,6 +58,14 @@ func (v *dummy) GetRepeatingPlans() []api.RepeatingPlan {
 	return nil
 }
 
+func (v *dummy) GetDepartureGuarantee() api.DepartureGuarantee {
+	return api.DepartureGuarantee{}
+}
+
+func (v *dummy) SetDepartureGuarantee(guarantee api.DepartureGuarantee) error {
+	return nil
+}
+
 func (v *dummy) GetPlanStrategy() api.PlanStrategy {
 	return api.PlanStrategy{}
 }
@@ -64,3 +73,11 @@ func (v *dummy) GetPlanStrategy() api.PlanStrategy {
 func (v *dummy) SetPlanStrategy(strategy api.PlanStrategy) error {
 	return nil
 }
+
+func (v *dummy) GetFingerprint() fingerprint.Profile {
+	return fingerprint.Profile{}
+}
+
+func (v *dummy) SetFingerprint(profile fingerprint.Profile) error {
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	time "time"
 
 	api "github.com/evcc-io/evcc/api"
+	fingerprint "github.com/evcc-io/evcc/core/fingerprint"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +42,34 @@ func (m *MockAPI) EXPECT() *MockAPIMockRecorder {
 	return m.recorder
 }
 
+// GetDepartureGuarantee mocks base method.
+func (m *MockAPI) GetDepartureGuarantee() api.DepartureGuarantee {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDepartureGuarantee")
+	ret0, _ := ret[0].(api.DepartureGuarantee)
+	return ret0
+}
+
+// GetDepartureGuarantee indicates an expected call of GetDepartureGuarantee.
+func (mr *MockAPIMockRecorder) GetDepartureGuarantee() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDepartureGuarantee", reflect.TypeOf((*MockAPI)(nil).GetDepartureGuarantee))
+}
+
+// GetFingerprint mocks base method.
+func (m *MockAPI) GetFingerprint() fingerprint.Profile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFingerprint")
+	ret0, _ := ret[0].(fingerprint.Profile)
+	return ret0
+}
+
+// GetFingerprint indicates an expected call of GetFingerprint.
+func (mr *MockAPIMockRecorder) GetFingerprint() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFingerprint", reflect.TypeOf((*MockAPI)(nil).GetFingerprint))
+}
+
 // GetLimitSoc mocks base method.
 func (m *MockAPI) GetLimitSoc() int {
 	m.ctrl.T.Helper()
@@ -140,6 +169,34 @@ func (mr *MockAPIMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockAPI)(nil).Name))
 }
 
+// SetDepartureGuarantee mocks base method.
+func (m *MockAPI) SetDepartureGuarantee(arg0 api.DepartureGuarantee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDepartureGuarantee", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDepartureGuarantee indicates an expected call of SetDepartureGuarantee.
+func (mr *MockAPIMockRecorder) SetDepartureGuarantee(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDepartureGuarantee", reflect.TypeOf((*MockAPI)(nil).SetDepartureGuarantee), arg0)
+}
+
+// SetFingerprint mocks base method.
+func (m *MockAPI) SetFingerprint(arg0 fingerprint.Profile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFingerprint", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFingerprint indicates an expected call of SetFingerprint.
+func (mr *MockAPIMockRecorder) SetFingerprint(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFingerprint", reflect.TypeOf((*MockAPI)(nil).SetFingerprint), arg0)
+}
+
 // SetLimitSoc mocks base method.
 func (m *MockAPI) SetLimitSoc(soc int) {
 	m.ctrl.T.Helper()
@@ -42,7 +42,7 @@ func NewFromConfig(ctx context.Context, other map[string]any, site site.API) (*F
 	}
 
 	// setup grid control circuit
-	gridcontrol, err := smartgrid.SetupCircuit()
+	gridcontrol, err := smartgrid.SetupCircuit(site.GetCircuit())
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func NewFromConfig(ctx context.Context, other map[string]any, site site.API) (*R
 	}
 
 	// setup grid control circuit
-	gridcontrol, err := smartgrid.SetupCircuit()
+	gridcontrol, err := smartgrid.SetupCircuit(site.GetCircuit())
 	if err != nil {
 		return nil, err
 	}
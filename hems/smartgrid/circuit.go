@@ -12,14 +12,12 @@ import (
 
 const GridControl = "gridcontrol"
 
-// SetupCircuit returns or registers the grid control circuit
-func SetupCircuit() (api.Circuit, error) {
+// SetupCircuit registers the grid control circuit as parent of the site's root circuit
+func SetupCircuit(root api.Circuit) (api.Circuit, error) {
 	if _, err := config.Circuits().ByName(GridControl); err == nil {
 		return nil, errors.New("gridcontrol is a reserved name and will be auto-created as root circuit when hems is configured")
 	}
 
-	root := circuit.Root()
-
 	// create new circuit
 	circuit, err := circuit.New(util.NewLogger(GridControl), "", 0, 0, nil, time.Minute)
 	if err != nil {
@@ -15,7 +15,8 @@ import (
 
 // Event is a notification event
 type Event struct {
-	Loadpoint *int // optional loadpoint id
+	Site      string // optional name of additional site
+	Loadpoint *int   // optional loadpoint id
 	Event     string
 }
 
@@ -71,6 +72,11 @@ func (h *Hub) Add(sender api.Messenger) {
 func (h *Hub) apply(ev Event, tmpl string) (string, error) {
 	attr := make(map[string]any)
 
+	// site name
+	if ev.Site != "" {
+		attr["site"] = ev.Site
+	}
+
 	// loadpoint id
 	if ev.Loadpoint != nil {
 		attr["loadpoint"] = *ev.Loadpoint + 1
@@ -78,7 +84,7 @@ func (h *Hub) apply(ev Event, tmpl string) (string, error) {
 
 	// get all values from cache
 	for _, p := range h.cache.All() {
-		if p.Loadpoint == nil || ev.Loadpoint == p.Loadpoint {
+		if p.Site == ev.Site && (p.Loadpoint == nil || ev.Loadpoint == p.Loadpoint) {
 			val := p.Val
 
 			// resolve pointers (https://github.com/evcc-io/evcc/issues/24688)
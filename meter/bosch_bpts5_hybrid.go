@@ -46,7 +46,7 @@ func NewBoschBpts5HybridFromConfig(other map[string]any) (api.Meter, error) {
 	if cc.Usage == "battery" {
 		return decorateMeterBattery(
 			m, nil, m.soc, cc.batteryCapacity.Decorator(),
-			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(), nil,
+			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(), nil, nil, nil,
 		), nil
 	}
 
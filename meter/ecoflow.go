@@ -78,7 +78,7 @@ func NewEcoFlowFromConfig(ctx context.Context, other map[string]any) (api.Meter,
 	if cc.Usage == "battery" {
 		return decorateMeterBattery(
 			m, nil, m.soc, cc.batteryCapacity.Decorator(),
-			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(), nil,
+			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(), nil, nil, nil,
 		), nil
 	}
 
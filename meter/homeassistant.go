@@ -91,15 +91,20 @@ func NewHomeAssistantFromConfig(other map[string]any) (api.Meter, error) {
 	if cc.Soc != "" {
 		socG := func() (float64, error) { return conn.GetFloatState(cc.Soc) }
 
-		return m.DecorateBattery(
-			energyG,
-			socG, cc.batteryCapacity.Decorator(),
-			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(),
-			nil,
-		), nil
+		return m.DecorateBattery(BatteryDecorators{
+			TotalEnergy: energyG,
+			Soc:         socG,
+			Capacity:    cc.batteryCapacity.Decorator(),
+			SocLimits:   cc.batterySocLimits.Decorator(),
+			PowerLimits: cc.batteryPowerLimits.Decorator(),
+		}), nil
 	}
 
-	return m.Decorate(
-		energyG, currentsG, voltagesG, powersG, cc.pvMaxACPower.Decorator(),
-	), nil
+	return m.Decorate(Decorators{
+		TotalEnergy: energyG,
+		Currents:    currentsG,
+		Voltages:    voltagesG,
+		Powers:      powersG,
+		MaxACPower:  cc.pvMaxACPower.Decorator(),
+	}), nil
 }
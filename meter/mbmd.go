@@ -135,10 +135,16 @@ func NewMbmdFromConfig(ctx context.Context, other map[string]any) (api.Meter, er
 	m, _ := NewConfigurable(powerG)
 
 	if soc != nil {
-		return m.DecorateBattery(totalEnergy, soc, cc.batteryCapacity.Decorator(), cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(), nil), nil
+		return m.DecorateBattery(BatteryDecorators{
+			TotalEnergy: totalEnergy,
+			Soc:         soc,
+			Capacity:    cc.batteryCapacity.Decorator(),
+			SocLimits:   cc.batterySocLimits.Decorator(),
+			PowerLimits: cc.batteryPowerLimits.Decorator(),
+		}), nil
 	}
 
-	return m.Decorate(totalEnergy, currentsG, voltagesG, powersG, nil), nil
+	return m.Decorate(Decorators{TotalEnergy: totalEnergy, Currents: currentsG, Voltages: voltagesG, Powers: powersG}), nil
 }
 
 // deviceOp checks is RS485 device supports operation
@@ -17,15 +17,26 @@ func (cc *Energy) Configure(ctx context.Context) (
 	func() (float64, error),
 	error,
 ) {
-	powerG, err := cc.Power.FloatGetter(ctx)
+	powerG, energyG, _, err := cc.ConfigureNotifier(ctx)
+	return powerG, energyG, err
+}
+
+// ConfigureNotifier additionally returns the update channel if the power plugin pushes values
+func (cc *Energy) ConfigureNotifier(ctx context.Context) (
+	func() (float64, error),
+	func() (float64, error),
+	<-chan struct{},
+	error,
+) {
+	powerG, updated, err := cc.Power.FloatGetterNotifier(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("power: %w", err)
+		return nil, nil, nil, fmt.Errorf("power: %w", err)
 	}
 
 	energyG, err := cc.Energy.FloatGetter(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("energy: %w", err)
+		return nil, nil, nil, fmt.Errorf("energy: %w", err)
 	}
 
-	return powerG, energyG, nil
+	return powerG, energyG, updated, nil
 }
@@ -2,7 +2,9 @@ package meter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/meter/measurement"
@@ -18,11 +20,11 @@ func init() {
 
 //evcc:function decorateMeter
 //evcc:basetype api.Meter
-//evcc:types api.MeterEnergy,api.PhaseCurrents,api.PhaseVoltages,api.PhasePowers,api.MaxACPowerGetter
+//evcc:types api.MeterEnergy,api.PhaseCurrents,api.PhaseVoltages,api.PhasePowers,api.MaxACPowerGetter,api.PowerLimiter,api.MeterUpdater,api.OffGridDetector
 
 //evcc:function decorateMeterBattery
 //evcc:basetype api.Meter
-//evcc:types api.MeterEnergy,api.Battery,api.BatteryCapacity,api.BatterySocLimiter,api.BatteryPowerLimiter,api.BatteryController
+//evcc:types api.MeterEnergy,api.Battery,api.BatteryCapacity,api.BatterySocLimiter,api.BatteryPowerLimiter,api.BatteryController,api.BatteryPowerController,api.OffGridDetector
 
 // NewConfigurableFromConfig creates api.Meter from config
 func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.Meter, error) {
@@ -32,6 +34,7 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.M
 
 		// pv
 		pvMaxACPower `mapstructure:",squash"`
+		LimitPower   *plugin.Config // optional
 
 		// battery
 		batteryCapacity    `mapstructure:",squash"`
@@ -40,6 +43,13 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.M
 		Soc                *plugin.Config // optional
 		LimitSoc           *plugin.Config // optional
 		BatteryMode        *plugin.Config // optional
+		BatteryPower       *plugin.Config // optional
+
+		// hybrid inverter
+		OffGrid *plugin.Config // optional
+
+		// push
+		Push time.Duration // minimum interval for triggering updates on pushed values
 	}{
 		batterySocLimits: batterySocLimits{
 			MinSoc: 20,
@@ -51,11 +61,27 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.M
 		return nil, err
 	}
 
-	powerG, energyG, err := cc.Energy.Configure(ctx)
+	powerG, energyG, updatedC, err := cc.Energy.ConfigureNotifier(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var updated func() <-chan struct{}
+	if cc.Push > 0 {
+		if cc.Soc != nil {
+			return nil, errors.New("push: not supported for battery meters")
+		}
+
+		if updatedC == nil {
+			return nil, errors.New("push: power source does not push values")
+		}
+
+		throttled := throttle(ctx, updatedC, cc.Push)
+		updated = func() <-chan struct{} {
+			return throttled
+		}
+	}
+
 	currentsG, voltagesG, powersG, err := cc.Phases.Configure(ctx)
 	if err != nil {
 		return nil, err
@@ -63,6 +89,12 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.M
 
 	m, _ := NewConfigurable(powerG)
 
+	// decorate off-grid detection
+	offGridG, err := cc.OffGrid.BoolGetter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("off-grid: %w", err)
+	}
+
 	// decorate soc
 	socG, err := cc.Soc.FloatGetter(ctx)
 	if err != nil {
@@ -91,18 +123,96 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.M
 		}
 	}
 
+	// battery power setpoint requires mode control for returning control to the battery
+	batPowerS, err := cc.BatteryPower.FloatSetter(ctx, "batteryPower")
+	if err != nil {
+		return nil, fmt.Errorf("battery power: %w", err)
+	}
+
+	if batPowerS != nil && batModeS == nil {
+		return nil, errors.New("battery power requires battery mode")
+	}
+
 	if socG != nil {
-		return m.DecorateBattery(
-			energyG,
-			socG, cc.batteryCapacity.Decorator(),
-			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(),
-			batModeS,
-		), nil
+		return m.DecorateBattery(BatteryDecorators{
+			TotalEnergy: energyG,
+			Soc:         socG,
+			Capacity:    cc.batteryCapacity.Decorator(),
+			SocLimits:   cc.batterySocLimits.Decorator(),
+			PowerLimits: cc.batteryPowerLimits.Decorator(),
+			SetMode:     batModeS,
+			SetPower:    batPowerS,
+			OffGrid:     offGridG,
+		}), nil
+	}
+
+	// decorate active power limit
+	limitPowerS, err := cc.LimitPower.FloatSetter(ctx, "limitPower")
+	if err != nil {
+		return nil, fmt.Errorf("limit power: %w", err)
 	}
 
-	return m.Decorate(
-		energyG, currentsG, voltagesG, powersG, cc.pvMaxACPower.Decorator(),
-	), nil
+	return m.Decorate(Decorators{
+		TotalEnergy: energyG,
+		Currents:    currentsG,
+		Voltages:    voltagesG,
+		Powers:      powersG,
+		MaxACPower:  cc.pvMaxACPower.Decorator(),
+		LimitPower:  limitPowerS,
+		Updated:     updated,
+		OffGrid:     offGridG,
+	}), nil
+}
+
+// throttle forwards updates at most once per interval. Updates received within
+// the interval trigger a trailing update when the interval ends.
+func throttle(ctx context.Context, in <-chan struct{}, interval time.Duration) <-chan struct{} {
+	out := make(chan struct{}, 1)
+
+	send := func() {
+		select {
+		case out <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		var (
+			timer   <-chan time.Time
+			pending bool
+		)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case _, ok := <-in:
+				if !ok {
+					return
+				}
+
+				if timer != nil {
+					pending = true
+					continue
+				}
+
+				send()
+				timer = time.After(interval)
+
+			case <-timer:
+				timer = nil
+
+				if pending {
+					pending = false
+					send()
+					timer = time.After(interval)
+				}
+			}
+		}
+	}()
+
+	return out
 }
 
 // NewConfigurable creates a new meter
@@ -118,29 +228,42 @@ type Meter struct {
 	currentPowerG func() (float64, error)
 }
 
+// Decorators are the optional capabilities of a meter, nil if not supported
+type Decorators struct {
+	TotalEnergy                func() (float64, error)
+	Currents, Voltages, Powers func() (float64, float64, float64, error)
+	MaxACPower                 func() float64
+	LimitPower                 func(float64) error
+	Updated                    func() <-chan struct{}
+	OffGrid                    func() (bool, error)
+}
+
+// BatteryDecorators are the optional capabilities of a battery meter, nil if not supported
+type BatteryDecorators struct {
+	TotalEnergy            func() (float64, error)
+	Soc                    func() (float64, error)
+	Capacity               func() float64
+	SocLimits, PowerLimits func() (float64, float64)
+	SetMode                func(api.BatteryMode) error
+	SetPower               func(float64) error
+	OffGrid                func() (bool, error)
+}
+
 // Decorate attaches additional capabilities to the base meter
-func (m *Meter) Decorate(
-	totalEnergy func() (float64, error),
-	currents, voltages, powers func() (float64, float64, float64, error),
-	maxACPower func() float64,
-) api.Meter {
+func (m *Meter) Decorate(d Decorators) api.Meter {
 	return decorateMeter(m,
-		totalEnergy, currents, voltages, powers,
-		maxACPower,
+		d.TotalEnergy, d.Currents, d.Voltages, d.Powers,
+		d.MaxACPower, d.LimitPower, d.Updated, d.OffGrid,
 	)
 }
 
-func (m *Meter) DecorateBattery(
-	totalEnergy func() (float64, error),
-	soc func() (float64, error), capacity func() float64,
-	socLimits, powerLimits func() (float64, float64),
-	setMode func(api.BatteryMode) error,
-) api.Meter {
+// DecorateBattery attaches additional capabilities to the base battery meter
+func (m *Meter) DecorateBattery(d BatteryDecorators) api.Meter {
 	return decorateMeterBattery(m,
-		totalEnergy,
-		soc, capacity,
-		socLimits, powerLimits,
-		setMode,
+		d.TotalEnergy,
+		d.Soc, d.Capacity,
+		d.SocLimits, d.PowerLimits,
+		d.SetMode, d.SetPower, d.OffGrid,
 	)
 }
 
@@ -71,10 +71,10 @@ func NewMovingAverageFromConfig(ctx context.Context, other map[string]any) (api.
 	}
 
 	if batterySoc != nil {
-		return meter.DecorateBattery(totalEnergy, batterySoc, cc.Meter.batteryCapacity.Decorator(), nil, nil, nil), nil
+		return meter.DecorateBattery(BatteryDecorators{TotalEnergy: totalEnergy, Soc: batterySoc, Capacity: cc.Meter.batteryCapacity.Decorator()}), nil
 	}
 
-	return meter.Decorate(totalEnergy, currents, voltages, powers, nil), nil
+	return meter.Decorate(Decorators{TotalEnergy: totalEnergy, Currents: currents, Voltages: voltages, Powers: powers}), nil
 }
 
 type MovingAverage struct {
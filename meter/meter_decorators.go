@@ -8,7 +8,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateMeter(base api.Meter, meterEnergy func() (float64, error), phaseCurrents func() (float64, float64, float64, error), phaseVoltages func() (float64, float64, float64, error), phasePowers func() (float64, float64, float64, error), maxACPowerGetter func() float64) api.Meter {
+func decorateMeter(base api.Meter, meterEnergy func() (float64, error), phaseCurrents func() (float64, float64, float64, error), phaseVoltages func() (float64, float64, float64, error), phasePowers func() (float64, float64, float64, error), maxACPowerGetter func() float64, powerLimiter func(float64) error, meterUpdater func() <-chan struct{}, offGridDetector func() (bool, error)) api.Meter {
 	caps := make(map[reflect.Type]any)
 
 	if meterEnergy != nil {
@@ -31,6 +31,18 @@ func decorateMeter(base api.Meter, meterEnergy func() (float64, error), phaseCur
 		caps[reflect.TypeFor[api.MaxACPowerGetter]()] = &decorateMeterMaxACPowerGetterImpl{maxACPowerGetter: maxACPowerGetter}
 	}
 
+	if powerLimiter != nil {
+		caps[reflect.TypeFor[api.PowerLimiter]()] = &decorateMeterPowerLimiterImpl{powerLimiter: powerLimiter}
+	}
+
+	if meterUpdater != nil {
+		caps[reflect.TypeFor[api.MeterUpdater]()] = &decorateMeterMeterUpdaterImpl{meterUpdater: meterUpdater}
+	}
+
+	if offGridDetector != nil {
+		caps[reflect.TypeFor[api.OffGridDetector]()] = &decorateMeterOffGridDetectorImpl{offGridDetector: offGridDetector}
+	}
+
 	if len(caps) == 0 {
 		return base
 	}
@@ -67,6 +79,22 @@ func (impl *decorateMeterMeterEnergyImpl) TotalEnergy() (float64, error) {
 	return impl.meterEnergy()
 }
 
+type decorateMeterMeterUpdaterImpl struct {
+	meterUpdater func() <-chan struct{}
+}
+
+func (impl *decorateMeterMeterUpdaterImpl) Updated() <-chan struct{} {
+	return impl.meterUpdater()
+}
+
+type decorateMeterOffGridDetectorImpl struct {
+	offGridDetector func() (bool, error)
+}
+
+func (impl *decorateMeterOffGridDetectorImpl) OffGrid() (bool, error) {
+	return impl.offGridDetector()
+}
+
 type decorateMeterPhaseCurrentsImpl struct {
 	phaseCurrents func() (float64, float64, float64, error)
 }
@@ -91,7 +119,15 @@ func (impl *decorateMeterPhaseVoltagesImpl) Voltages() (float64, float64, float6
 	return impl.phaseVoltages()
 }
 
-func decorateMeterBattery(base api.Meter, meterEnergy func() (float64, error), battery func() (float64, error), batteryCapacity func() float64, batterySocLimiter func() (float64, float64), batteryPowerLimiter func() (float64, float64), batteryController func(api.BatteryMode) error) api.Meter {
+type decorateMeterPowerLimiterImpl struct {
+	powerLimiter func(float64) error
+}
+
+func (impl *decorateMeterPowerLimiterImpl) LimitPower(p0 float64) error {
+	return impl.powerLimiter(p0)
+}
+
+func decorateMeterBattery(base api.Meter, meterEnergy func() (float64, error), battery func() (float64, error), batteryCapacity func() float64, batterySocLimiter func() (float64, float64), batteryPowerLimiter func() (float64, float64), batteryController func(api.BatteryMode) error, batteryPowerController func(float64) error, offGridDetector func() (bool, error)) api.Meter {
 	caps := make(map[reflect.Type]any)
 
 	if meterEnergy != nil {
@@ -118,6 +154,14 @@ func decorateMeterBattery(base api.Meter, meterEnergy func() (float64, error), b
 		caps[reflect.TypeFor[api.BatteryController]()] = &decorateMeterBatteryBatteryControllerImpl{batteryController: batteryController}
 	}
 
+	if batteryPowerController != nil {
+		caps[reflect.TypeFor[api.BatteryPowerController]()] = &decorateMeterBatteryBatteryPowerControllerImpl{batteryPowerController: batteryPowerController}
+	}
+
+	if offGridDetector != nil {
+		caps[reflect.TypeFor[api.OffGridDetector]()] = &decorateMeterBatteryOffGridDetectorImpl{offGridDetector: offGridDetector}
+	}
+
 	if len(caps) == 0 {
 		return base
 	}
@@ -162,6 +206,14 @@ func (impl *decorateMeterBatteryBatteryControllerImpl) SetBatteryMode(p0 api.Bat
 	return impl.batteryController(p0)
 }
 
+type decorateMeterBatteryBatteryPowerControllerImpl struct {
+	batteryPowerController func(float64) error
+}
+
+func (impl *decorateMeterBatteryBatteryPowerControllerImpl) SetBatteryPower(p0 float64) error {
+	return impl.batteryPowerController(p0)
+}
+
 type decorateMeterBatteryBatteryPowerLimiterImpl struct {
 	batteryPowerLimiter func() (float64, float64)
 }
@@ -185,3 +237,11 @@ type decorateMeterBatteryMeterEnergyImpl struct {
 func (impl *decorateMeterBatteryMeterEnergyImpl) TotalEnergy() (float64, error) {
 	return impl.meterEnergy()
 }
+
+type decorateMeterBatteryOffGridDetectorImpl struct {
+	offGridDetector func() (bool, error)
+}
+
+func (impl *decorateMeterBatteryOffGridDetectorImpl) OffGrid() (bool, error) {
+	return impl.offGridDetector()
+}
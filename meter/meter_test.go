@@ -2,6 +2,7 @@ package meter
 
 import (
 	"testing"
+	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/stretchr/testify/assert"
@@ -41,3 +42,48 @@ func TestBattery(t *testing.T) {
 	_, ok = api.Cap[api.BatteryCapacity](m)
 	assert.True(t, ok, "BatteryCapacity")
 }
+
+func TestPushUnsupported(t *testing.T) {
+	_, err := NewConfigurableFromConfig(t.Context(), map[string]any{
+		"power": map[string]any{
+			"source": "const",
+			"value":  1000,
+		},
+		"push": "1s",
+	})
+	require.Error(t, err)
+}
+
+func TestPushBattery(t *testing.T) {
+	_, err := NewConfigurableFromConfig(t.Context(), map[string]any{
+		"power": map[string]any{
+			"source": "const",
+			"value":  1000,
+		},
+		"soc": map[string]any{
+			"source": "const",
+			"value":  47,
+		},
+		"push": "1s",
+	})
+	require.Error(t, err)
+}
+
+func TestThrottle(t *testing.T) {
+	in := make(chan struct{})
+	out := throttle(t.Context(), in, 100*time.Millisecond)
+
+	in <- struct{}{}
+	in <- struct{}{}
+	in <- struct{}{}
+
+	// leading edge
+	assert.Eventually(t, func() bool { return len(out) == 1 }, time.Second, 10*time.Millisecond)
+	<-out
+	assert.Never(t, func() bool { return len(out) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+
+	// trailing edge
+	assert.Eventually(t, func() bool { return len(out) == 1 }, time.Second, 10*time.Millisecond)
+	<-out
+	assert.Never(t, func() bool { return len(out) > 0 }, 250*time.Millisecond, 10*time.Millisecond)
+}
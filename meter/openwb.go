@@ -140,8 +140,8 @@ func NewOpenWBFromConfig(other map[string]any) (api.Meter, error) {
 	}
 
 	if strings.ToLower(cc.Usage) == "battery" {
-		return m.DecorateBattery(nil, soc, capacity, nil, nil, nil), nil
+		return m.DecorateBattery(BatteryDecorators{Soc: soc, Capacity: capacity}), nil
 	}
 
-	return m.Decorate(nil, currents, nil, nil, nil), nil
+	return m.Decorate(Decorators{Currents: currents}), nil
 }
@@ -52,7 +52,7 @@ func NewSMAFromConfig(other map[string]any) (api.Meter, error) {
 		return decorateMeterBattery(
 			sm, sm.TotalEnergy,
 			sm.soc, cc.batteryCapacity.Decorator(),
-			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(), nil,
+			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(), nil, nil, nil,
 		), nil
 	}
 
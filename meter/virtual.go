@@ -0,0 +1,336 @@
+package meter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// Virtual is the virtual meter type
+const Virtual = "virtual"
+
+func init() {
+	registry.AddCtx(Virtual, NewVirtualFromConfig)
+}
+
+const (
+	virtualSignPositive = "positive"
+	virtualSignNegative = "negative"
+)
+
+// virtualRef is a reference to another configured meter
+type virtualRef struct {
+	Meter  string  // meter name
+	Factor float64 // scale factor, use -1 to subtract, defaults to 1
+	Sign   string  // only use positive or negative power readings
+}
+
+// virtualReading is the last power reading of a referenced meter
+type virtualReading struct {
+	power   float64
+	changed time.Time
+}
+
+// VirtualMeter is a meter combining other configured meters
+type VirtualMeter struct {
+	mu       sync.Mutex
+	clock    clock.Clock
+	refs     []virtualRef
+	timeout  time.Duration
+	readings []virtualReading
+}
+
+// VirtualRefs returns the names of the meters referenced by a virtual meter config
+func VirtualRefs(other map[string]any) ([]string, error) {
+	var cc struct {
+		Meters []virtualRef
+		Other  map[string]any `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, ref := range cc.Meters {
+		if ref.Meter != "" && !slices.Contains(res, ref.Meter) {
+			res = append(res, ref.Meter)
+		}
+	}
+
+	return res, nil
+}
+
+// NewVirtualFromConfig creates api.Meter from config
+func NewVirtualFromConfig(ctx context.Context, other map[string]any) (api.Meter, error) {
+	var cc struct {
+		Meters  []virtualRef
+		Timeout time.Duration
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if len(cc.Meters) == 0 {
+		return nil, errors.New("missing meters")
+	}
+
+	if cc.Timeout < 0 {
+		return nil, errors.New("timeout must not be negative")
+	}
+
+	for i, ref := range cc.Meters {
+		if ref.Meter == "" {
+			return nil, fmt.Errorf("meter %d: missing name", i+1)
+		}
+
+		if ref.Factor == 0 {
+			cc.Meters[i].Factor = 1
+		}
+
+		if !slices.Contains([]string{"", virtualSignPositive, virtualSignNegative}, ref.Sign) {
+			return nil, fmt.Errorf("meter %d: invalid sign: %s", i+1, ref.Sign)
+		}
+	}
+
+	return newVirtual(clock.New(), cc.Meters, cc.Timeout)
+}
+
+// newVirtual creates a virtual meter. Referenced meters must already be configured.
+// Power readings of referenced meters that remain unchanged and non-zero for longer
+// than timeout are considered stale.
+func newVirtual(clock clock.Clock, refs []virtualRef, timeout time.Duration) (api.Meter, error) {
+	v := &VirtualMeter{
+		clock:    clock,
+		refs:     refs,
+		timeout:  timeout,
+		readings: make([]virtualReading, len(refs)),
+	}
+
+	// capabilities are determined by the referenced meters at creation time
+	meters, err := v.meters()
+	if err != nil {
+		return nil, err
+	}
+
+	// sign rules only apply to power
+	signed := slices.ContainsFunc(refs, func(ref virtualRef) bool {
+		return ref.Sign != ""
+	})
+
+	var totalEnergy func() (float64, error)
+	if !signed && allCap[api.MeterEnergy](meters) {
+		totalEnergy = v.totalEnergy
+	}
+
+	var currents func() (float64, float64, float64, error)
+	if !signed && allCap[api.PhaseCurrents](meters) {
+		currents = v.currents
+	}
+
+	var powers func() (float64, float64, float64, error)
+	if !signed && allCap[api.PhasePowers](meters) {
+		powers = v.powers
+	}
+
+	var voltages func() (float64, float64, float64, error)
+	if slices.ContainsFunc(meters, func(m api.Meter) bool {
+		return api.HasCap[api.PhaseVoltages](m)
+	}) {
+		voltages = v.voltages
+	}
+
+	m, _ := NewConfigurable(v.currentPower)
+
+	return m.Decorate(Decorators{
+		TotalEnergy: totalEnergy,
+		Currents:    currents,
+		Voltages:    voltages,
+		Powers:      powers,
+	}), nil
+}
+
+// allCap returns true if all meters provide capability T
+func allCap[T any](meters []api.Meter) bool {
+	return !slices.ContainsFunc(meters, func(m api.Meter) bool {
+		return !api.HasCap[T](m)
+	})
+}
+
+// meters resolves the referenced meters
+func (v *VirtualMeter) meters() ([]api.Meter, error) {
+	res := make([]api.Meter, 0, len(v.refs))
+
+	for _, ref := range v.refs {
+		dev, err := config.Meters().ByName(ref.Meter)
+		if err != nil {
+			return nil, err
+		}
+
+		m := dev.Instance()
+		if m == nil {
+			return nil, fmt.Errorf("%s: %w", ref.Meter, api.ErrNotAvailable)
+		}
+
+		res = append(res, m)
+	}
+
+	return res, nil
+}
+
+// sum combines the referenced meter's readings
+func (v *VirtualMeter) sum(fun func(m api.Meter) (float64, error)) (float64, error) {
+	meters, err := v.meters()
+	if err != nil {
+		return 0, err
+	}
+
+	var res float64
+
+	for i, ref := range v.refs {
+		f, err := fun(meters[i])
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", ref.Meter, err)
+		}
+
+		res += ref.Factor * f
+	}
+
+	return res, nil
+}
+
+// sumPhases combines the referenced meter's phase readings
+func (v *VirtualMeter) sumPhases(fun func(m api.Meter) (float64, float64, float64, error)) (float64, float64, float64, error) {
+	meters, err := v.meters()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var res [3]float64
+
+	for i, ref := range v.refs {
+		l1, l2, l3, err := fun(meters[i])
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("%s: %w", ref.Meter, err)
+		}
+
+		for j, f := range []float64{l1, l2, l3} {
+			res[j] += ref.Factor * f
+		}
+	}
+
+	return res[0], res[1], res[2], nil
+}
+
+// stale returns api.ErrOutdated if the i-th referenced meter's non-zero power
+// has not changed within timeout. Zero power is a legitimate steady state.
+func (v *VirtualMeter) stale(i int, power float64) error {
+	if v.timeout == 0 {
+		return nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.clock.Now()
+	r := &v.readings[i]
+
+	if r.changed.IsZero() || power != r.power || power == 0 {
+		r.power = power
+		r.changed = now
+		return nil
+	}
+
+	if d := now.Sub(r.changed); d > v.timeout {
+		return fmt.Errorf("%w: %.0fW unchanged for %v", api.ErrOutdated, power, d.Truncate(time.Second))
+	}
+
+	return nil
+}
+
+func (v *VirtualMeter) currentPower() (float64, error) {
+	meters, err := v.meters()
+	if err != nil {
+		return 0, err
+	}
+
+	var res float64
+
+	for i, ref := range v.refs {
+		f, err := meters[i].CurrentPower()
+		if err == nil {
+			err = v.stale(i, f)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", ref.Meter, err)
+		}
+
+		switch ref.Sign {
+		case virtualSignPositive:
+			f = math.Max(f, 0)
+		case virtualSignNegative:
+			f = math.Min(f, 0)
+		}
+
+		res += ref.Factor * f
+	}
+
+	return res, nil
+}
+
+func (v *VirtualMeter) totalEnergy() (float64, error) {
+	return v.sum(func(m api.Meter) (float64, error) {
+		if m, ok := api.Cap[api.MeterEnergy](m); ok {
+			return m.TotalEnergy()
+		}
+		return 0, api.ErrNotAvailable
+	})
+}
+
+func (v *VirtualMeter) currents() (float64, float64, float64, error) {
+	return v.sumPhases(func(m api.Meter) (float64, float64, float64, error) {
+		if m, ok := api.Cap[api.PhaseCurrents](m); ok {
+			return m.Currents()
+		}
+		return 0, 0, 0, api.ErrNotAvailable
+	})
+}
+
+func (v *VirtualMeter) powers() (float64, float64, float64, error) {
+	return v.sumPhases(func(m api.Meter) (float64, float64, float64, error) {
+		if m, ok := api.Cap[api.PhasePowers](m); ok {
+			return m.Powers()
+		}
+		return 0, 0, 0, api.ErrNotAvailable
+	})
+}
+
+// voltages are not additive and taken from the first meter providing them
+func (v *VirtualMeter) voltages() (float64, float64, float64, error) {
+	meters, err := v.meters()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	for i, m := range meters {
+		if m, ok := api.Cap[api.PhaseVoltages](m); ok {
+			l1, l2, l3, err := m.Voltages()
+			if err != nil {
+				err = fmt.Errorf("%s: %w", v.refs[i].Meter, err)
+			}
+			return l1, l2, l3, err
+		}
+	}
+
+	return 0, 0, 0, api.ErrNotAvailable
+}
@@ -0,0 +1,129 @@
+package meter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addVirtualTestMeter(t *testing.T, name string, m api.Meter) {
+	t.Helper()
+	require.NoError(t, config.Meters().Add(config.NewStaticDevice(config.Named{Name: name}, m)))
+}
+
+func TestVirtual(t *testing.T) {
+	config.Reset()
+	t.Cleanup(config.Reset)
+
+	grid, _ := NewConfigurable(func() (float64, error) { return 1000, nil })
+	addVirtualTestMeter(t, "grid", grid.Decorate(Decorators{TotalEnergy: func() (float64, error) { return 10, nil }}))
+
+	pv, _ := NewConfigurable(func() (float64, error) { return 3000, nil })
+	addVirtualTestMeter(t, "pv", pv.Decorate(Decorators{TotalEnergy: func() (float64, error) { return 4, nil }}))
+
+	battery, _ := NewConfigurable(func() (float64, error) { return -500, nil })
+	addVirtualTestMeter(t, "battery", battery)
+
+	// house = grid + pv + battery
+	m, err := NewVirtualFromConfig(t.Context(), map[string]any{
+		"meters": []map[string]any{
+			{"meter": "grid"},
+			{"meter": "pv"},
+		},
+	})
+	require.NoError(t, err)
+
+	f, err := m.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 4000.0, f)
+
+	em, ok := api.Cap[api.MeterEnergy](m)
+	require.True(t, ok, "MeterEnergy")
+	f, err = em.TotalEnergy()
+	require.NoError(t, err)
+	assert.Equal(t, 14.0, f)
+
+	// factor and sign rules
+	m, err = NewVirtualFromConfig(t.Context(), map[string]any{
+		"meters": []map[string]any{
+			{"meter": "pv"},
+			{"meter": "battery", "factor": -1, "sign": "negative"},
+		},
+	})
+	require.NoError(t, err)
+
+	f, err = m.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 3500.0, f)
+
+	// energy not combined with sign rules
+	assert.False(t, api.HasCap[api.MeterEnergy](m), "MeterEnergy")
+}
+
+func TestVirtualErrors(t *testing.T) {
+	config.Reset()
+	t.Cleanup(config.Reset)
+
+	_, err := NewVirtualFromConfig(t.Context(), map[string]any{
+		"meters": []map[string]any{{"meter": "missing"}},
+	})
+	require.Error(t, err)
+
+	_, err = NewVirtualFromConfig(t.Context(), map[string]any{
+		"meters": []map[string]any{{"meter": "foo", "sign": "invalid"}},
+	})
+	require.Error(t, err)
+
+	failing, _ := NewConfigurable(func() (float64, error) { return 0, api.ErrTimeout })
+	addVirtualTestMeter(t, "failing", failing)
+
+	m, err := NewVirtualFromConfig(t.Context(), map[string]any{
+		"meters": []map[string]any{{"meter": "failing"}},
+	})
+	require.NoError(t, err)
+
+	_, err = m.CurrentPower()
+	assert.True(t, errors.Is(err, api.ErrTimeout))
+}
+
+func TestVirtualStale(t *testing.T) {
+	config.Reset()
+	t.Cleanup(config.Reset)
+
+	var grid, pv float64 = 1000, 0
+
+	gm, _ := NewConfigurable(func() (float64, error) { return grid, nil })
+	addVirtualTestMeter(t, "grid", gm)
+
+	pm, _ := NewConfigurable(func() (float64, error) { return pv, nil })
+	addVirtualTestMeter(t, "pv", pm)
+
+	clock := clock.NewMock()
+	m, err := newVirtual(clock, []virtualRef{{Meter: "grid", Factor: 1}, {Meter: "pv", Factor: 1}}, time.Minute)
+	require.NoError(t, err)
+
+	_, err = m.CurrentPower()
+	require.NoError(t, err)
+
+	clock.Add(30 * time.Second)
+	_, err = m.CurrentPower()
+	require.NoError(t, err)
+
+	// unchanged non-zero grid reading is stale, zero pv reading is not
+	clock.Add(time.Minute)
+	_, err = m.CurrentPower()
+	require.ErrorIs(t, err, api.ErrOutdated)
+	assert.ErrorContains(t, err, "grid")
+
+	// changed reading recovers
+	grid = 1100
+	f, err := m.CurrentPower()
+	require.NoError(t, err)
+	assert.Equal(t, 1100.0, f)
+}
@@ -50,7 +50,7 @@ func NewZendureFromConfig(other map[string]any) (api.Meter, error) {
 	if cc.Usage == "battery" {
 		return decorateMeterBattery(
 			m, nil, m.soc, cc.batteryCapacity.Decorator(),
-			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(), nil,
+			cc.batterySocLimits.Decorator(), cc.batteryPowerLimits.Decorator(), nil, nil, nil,
 		), nil
 	}
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/evcc-io/evcc/util/recorder"
 	reg "github.com/evcc-io/evcc/util/registry"
 )
 
@@ -46,6 +47,9 @@ type (
 	BytesSetter interface {
 		BytesSetter(param string) (func([]byte) error, error)
 	}
+	Notifier interface {
+		Updated() <-chan struct{}
+	}
 )
 
 // Config is the general plugin config
@@ -84,82 +88,151 @@ func plugin[T any](typ string, ctx context.Context, config *Config) (T, error) {
 }
 
 func (c *Config) IntGetter(ctx context.Context) (func() (int64, error), error) {
+	if c != nil && recorder.Replaying() {
+		return replayGetter[int64](c, "int"), nil
+	}
+
 	prov, err := plugin[IntGetter]("int", ctx, c)
 	if prov == nil || err != nil {
 		return nil, err
 	}
 
-	return prov.IntGetter()
+	g, err := prov.IntGetter()
+	return recordGetter(c, "int", g, err)
 }
 
 func (c *Config) FloatGetter(ctx context.Context) (func() (float64, error), error) {
+	if c != nil && recorder.Replaying() {
+		return replayGetter[float64](c, "float"), nil
+	}
+
 	prov, err := plugin[FloatGetter]("float", ctx, c)
 	if prov == nil || err != nil {
 		return nil, err
 	}
 
-	return prov.FloatGetter()
+	g, err := prov.FloatGetter()
+	return recordGetter(c, "float", g, err)
+}
+
+// FloatGetterNotifier creates a float getter and returns the update channel if the plugin pushes values
+func (c *Config) FloatGetterNotifier(ctx context.Context) (func() (float64, error), <-chan struct{}, error) {
+	if c != nil && recorder.Replaying() {
+		return replayGetter[float64](c, "float"), nil, nil
+	}
+
+	prov, err := plugin[FloatGetter]("float", ctx, c)
+	if prov == nil || err != nil {
+		return nil, nil, err
+	}
+
+	g, err := prov.FloatGetter()
+	if g, err = recordGetter(c, "float", g, err); err != nil {
+		return nil, nil, err
+	}
+
+	var updated <-chan struct{}
+	if n, ok := prov.(Notifier); ok {
+		updated = n.Updated()
+	}
+
+	return g, updated, nil
 }
 
 func (c *Config) StringGetter(ctx context.Context) (func() (string, error), error) {
+	if c != nil && recorder.Replaying() {
+		return replayGetter[string](c, "string"), nil
+	}
+
 	prov, err := plugin[StringGetter]("string", ctx, c)
 	if prov == nil || err != nil {
 		return nil, err
 	}
 
-	return prov.StringGetter()
+	g, err := prov.StringGetter()
+	return recordGetter(c, "string", g, err)
 }
 
 func (c *Config) BoolGetter(ctx context.Context) (func() (bool, error), error) {
+	if c != nil && recorder.Replaying() {
+		return replayGetter[bool](c, "bool"), nil
+	}
+
 	prov, err := plugin[BoolGetter]("bool", ctx, c)
 	if prov == nil || err != nil {
 		return nil, err
 	}
 
-	return prov.BoolGetter()
+	g, err := prov.BoolGetter()
+	return recordGetter(c, "bool", g, err)
 }
 
 func (c *Config) IntSetter(ctx context.Context, param string) (func(int64) error, error) {
+	if c != nil && recorder.Replaying() {
+		return replaySetter[int64](c, "int", param), nil
+	}
+
 	prov, err := plugin[IntSetter]("int", ctx, c)
 	if prov == nil || err != nil {
 		return nil, err
 	}
 
-	return prov.IntSetter(param)
+	s, err := prov.IntSetter(param)
+	return recordSetter(c, "int", param, s, err)
 }
 
 func (c *Config) FloatSetter(ctx context.Context, param string) (func(float642 float64) error, error) {
+	if c != nil && recorder.Replaying() {
+		return replaySetter[float64](c, "float", param), nil
+	}
+
 	prov, err := plugin[FloatSetter]("float", ctx, c)
 	if prov == nil || err != nil {
 		return nil, err
 	}
 
-	return prov.FloatSetter(param)
+	s, err := prov.FloatSetter(param)
+	return recordSetter(c, "float", param, s, err)
 }
 
 func (c *Config) StringSetter(ctx context.Context, param string) (func(string) error, error) {
+	if c != nil && recorder.Replaying() {
+		return replaySetter[string](c, "string", param), nil
+	}
+
 	prov, err := plugin[StringSetter]("string", ctx, c)
 	if prov == nil || err != nil {
 		return nil, err
 	}
 
-	return prov.StringSetter(param)
+	s, err := prov.StringSetter(param)
+	return recordSetter(c, "string", param, s, err)
 }
 
 func (c *Config) BoolSetter(ctx context.Context, param string) (func(bool) error, error) {
+	if c != nil && recorder.Replaying() {
+		return replaySetter[bool](c, "bool", param), nil
+	}
+
 	prov, err := plugin[BoolSetter]("bool", ctx, c)
 	if prov == nil || err != nil {
 		return nil, err
 	}
 
-	return prov.BoolSetter(param)
+	s, err := prov.BoolSetter(param)
+	return recordSetter(c, "bool", param, s, err)
 }
 
 func (c *Config) BytesSetter(ctx context.Context, param string) (func([]byte) error, error) {
+	if c != nil && recorder.Replaying() {
+		return replaySetter[[]byte](c, "bytes", param), nil
+	}
+
 	prov, err := plugin[BytesSetter]("bytes", ctx, c)
 	if prov == nil || err != nil {
 		return nil, err
 	}
 
-	return prov.BytesSetter(param)
+	s, err := prov.BytesSetter(param)
+	return recordSetter(c, "bytes", param, s, err)
 }
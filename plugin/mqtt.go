@@ -19,6 +19,7 @@ type Mqtt struct {
 	payload  string
 	timeout  time.Duration
 	pipeline *pipeline.Pipeline
+	updated  chan struct{}
 }
 
 func init() {
@@ -69,6 +70,7 @@ func NewMqtt(log *util.Logger, client *mqtt.Client, topic string, timeout time.D
 		client:  client,
 		topic:   topic,
 		timeout: timeout,
+		updated: make(chan struct{}, 1),
 	}
 
 	m.getter = defaultGetters(m, 1)
@@ -106,6 +108,7 @@ func (m *Mqtt) newReceiver() (*msgHandler, error) {
 		topic:    m.topic,
 		pipeline: m.pipeline,
 		val:      util.NewMonitor[string](m.timeout),
+		updated:  m.updated,
 	}
 
 	err := m.client.Listen(m.topic, h.receive)
@@ -120,6 +123,13 @@ func (m *Mqtt) StringGetter() (func() (string, error), error) {
 	return h.value, err
 }
 
+var _ Notifier = (*Mqtt)(nil)
+
+// Updated returns a channel signalling received messages
+func (m *Mqtt) Updated() <-chan struct{} {
+	return m.updated
+}
+
 var _ IntSetter = (*Mqtt)(nil)
 
 // IntSetter publishes topic with parameter replaced by int value
@@ -9,10 +9,17 @@ type msgHandler struct {
 	topic    string
 	pipeline *pipeline.Pipeline
 	val      *util.Monitor[string]
+	updated  chan struct{}
 }
 
 func (h *msgHandler) receive(payload string) {
 	h.val.Set(payload)
+
+	// signal update without blocking
+	select {
+	case h.updated <- struct{}{}:
+	default:
+	}
 }
 
 // hasValue returned the received and processed payload as string
@@ -0,0 +1,185 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+)
+
+var errFrozen = errors.New("frozen value")
+
+// plausiblePlugin filters implausible readings of the wrapped value
+type plausiblePlugin struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	log     *util.Logger
+	clock   clock.Clock
+	value   Config
+	min     *float64
+	max     *float64
+	maxRate float64
+	median  int
+	frozen  time.Duration
+	hold    bool
+
+	// state
+	raw     *float64  // last raw reading
+	changed time.Time // last raw value change
+	valid   *float64  // last accepted reading
+	updated time.Time // last accepted reading timestamp
+	window  []float64 // last accepted readings for median
+}
+
+func init() {
+	registry.AddCtx("plausible", NewPlausibleFromConfig)
+}
+
+// NewPlausibleFromConfig creates plausible provider
+func NewPlausibleFromConfig(ctx context.Context, other map[string]any) (Plugin, error) {
+	var cc struct {
+		Value    Config
+		Min, Max *float64
+		MaxRate  float64 // max change per second
+		Median   int     // median of last n readings
+		Frozen   time.Duration
+		Hold     bool // hold last valid value instead of returning an error
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Min != nil && cc.Max != nil && *cc.Min > *cc.Max {
+		return nil, errors.New("min must not be greater than max")
+	}
+
+	if cc.MaxRate < 0 || cc.Median < 0 || cc.Frozen < 0 {
+		return nil, errors.New("maxrate, median and frozen must not be negative")
+	}
+
+	o := &plausiblePlugin{
+		ctx:     ctx,
+		log:     util.ContextLoggerWithDefault(ctx, util.NewLogger("plausible")),
+		clock:   clock.New(),
+		value:   cc.Value,
+		min:     cc.Min,
+		max:     cc.Max,
+		maxRate: cc.MaxRate,
+		median:  cc.Median,
+		frozen:  cc.Frozen,
+		hold:    cc.Hold,
+	}
+
+	return o, nil
+}
+
+// check returns an error if the reading is frozen or implausible
+func (o *plausiblePlugin) check(v float64, now time.Time) error {
+	if o.raw == nil || *o.raw != v {
+		o.raw = &v
+		o.changed = now
+	} else if o.frozen > 0 && now.Sub(o.changed) > o.frozen {
+		return fmt.Errorf("%w: %v unchanged for %v", errFrozen, v, now.Sub(o.changed).Truncate(time.Second))
+	}
+
+	if o.min != nil && v < *o.min {
+		return fmt.Errorf("implausible value: %v below min %v", v, *o.min)
+	}
+
+	if o.max != nil && v > *o.max {
+		return fmt.Errorf("implausible value: %v above max %v", v, *o.max)
+	}
+
+	if o.maxRate > 0 && o.valid != nil {
+		if dt := now.Sub(o.updated).Seconds(); dt > 0 {
+			if rate := math.Abs(v-*o.valid) / dt; rate > o.maxRate {
+				return fmt.Errorf("implausible value: %v changed by %.1f/s", v, rate)
+			}
+		}
+	}
+
+	return nil
+}
+
+// process validates the reading and returns the filtered value
+func (o *plausiblePlugin) process(v float64) (float64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := o.clock.Now()
+
+	if err := o.check(v, now); err != nil {
+		// frozen values are never held to signal stale readings
+		if o.hold && o.valid != nil && !errors.Is(err, errFrozen) {
+			o.log.DEBUG.Printf("%v, holding %v", err, o.output())
+			return o.output(), nil
+		}
+
+		return 0, err
+	}
+
+	o.valid = &v
+	o.updated = now
+
+	if o.median > 1 {
+		o.window = append(o.window, v)
+		if len(o.window) > o.median {
+			o.window = o.window[len(o.window)-o.median:]
+		}
+	}
+
+	return o.output(), nil
+}
+
+// output returns the last valid value or the median of the last valid values
+func (o *plausiblePlugin) output() float64 {
+	if len(o.window) == 0 {
+		return *o.valid
+	}
+
+	sorted := slices.Sorted(slices.Values(o.window))
+	if n := len(sorted); n%2 == 0 {
+		return (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	return sorted[len(sorted)/2]
+}
+
+var _ FloatGetter = (*plausiblePlugin)(nil)
+
+func (o *plausiblePlugin) FloatGetter() (func() (float64, error), error) {
+	g, err := o.value.FloatGetter(o.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("plausible: %w", err)
+	}
+
+	return func() (float64, error) {
+		v, err := g()
+		if err != nil {
+			return 0, err
+		}
+
+		return o.process(v)
+	}, nil
+}
+
+var _ IntGetter = (*plausiblePlugin)(nil)
+
+func (o *plausiblePlugin) IntGetter() (func() (int64, error), error) {
+	g, err := o.FloatGetter()
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (int64, error) {
+		v, err := g()
+		return int64(math.Round(v)), err
+	}, nil
+}
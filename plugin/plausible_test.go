@@ -0,0 +1,97 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPlausible() (*plausiblePlugin, *clock.Mock) {
+	clock := clock.NewMock()
+	return &plausiblePlugin{
+		log:   util.NewLogger("foo"),
+		clock: clock,
+	}, clock
+}
+
+func TestPlausibleBounds(t *testing.T) {
+	o, _ := newTestPlausible()
+	o.min = new(-1000.0)
+	o.max = new(1000.0)
+
+	v, err := o.process(500)
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, v)
+
+	_, err = o.process(60000)
+	assert.Error(t, err)
+
+	_, err = o.process(-60000)
+	assert.Error(t, err)
+
+	// hold last valid value
+	o.hold = true
+	v, err = o.process(60000)
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, v)
+}
+
+func TestPlausibleRate(t *testing.T) {
+	o, clock := newTestPlausible()
+	o.maxRate = 100
+
+	_, err := o.process(0)
+	require.NoError(t, err)
+
+	clock.Add(time.Second)
+	_, err = o.process(1000)
+	assert.Error(t, err)
+
+	// accepted after enough time has passed since last valid reading
+	clock.Add(9 * time.Second)
+	v, err := o.process(1000)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, v)
+}
+
+func TestPlausibleMedian(t *testing.T) {
+	o, _ := newTestPlausible()
+	o.median = 3
+
+	for _, tc := range []struct{ in, out float64 }{
+		{1, 1},
+		{3, 2},
+		{100, 3},
+		{2, 3},
+		{4, 4},
+	} {
+		v, err := o.process(tc.in)
+		require.NoError(t, err)
+		assert.Equal(t, tc.out, v, "in %v", tc.in)
+	}
+}
+
+func TestPlausibleFrozen(t *testing.T) {
+	o, clock := newTestPlausible()
+	o.frozen = time.Minute
+	o.hold = true
+
+	_, err := o.process(1)
+	require.NoError(t, err)
+
+	clock.Add(time.Minute)
+	_, err = o.process(1)
+	require.NoError(t, err)
+
+	clock.Add(time.Second)
+	_, err = o.process(1)
+	assert.ErrorIs(t, err, errFrozen)
+
+	v, err := o.process(2)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, v)
+}
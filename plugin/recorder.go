@@ -0,0 +1,75 @@
+package plugin
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/util/recorder"
+)
+
+// recorderKey identifies the plugin configuration in recordings
+func (c *Config) recorderKey(typ string) string {
+	return recorder.Key(typ, *c)
+}
+
+// recordGetter wraps the getter for recording its results if recording is enabled
+func recordGetter[T any](c *Config, typ string, g func() (T, error), err error) (func() (T, error), error) {
+	if g == nil || err != nil || !recorder.Recording() {
+		return g, err
+	}
+
+	key := c.recorderKey(typ)
+
+	return func() (T, error) {
+		res, err := g()
+		recorder.Add(recorder.Get, key, res, err)
+		return res, err
+	}, nil
+}
+
+// replayGetter returns a getter replaying recorded results
+func replayGetter[T any](c *Config, typ string) func() (T, error) {
+	key := c.recorderKey(typ)
+
+	return func() (T, error) {
+		e, err := recorder.Next(recorder.Get, key)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+
+		return recorder.Decode[T](e)
+	}
+}
+
+// recordSetter wraps the setter for recording its invocations if recording is enabled
+func recordSetter[T any](c *Config, typ, param string, s func(T) error, err error) (func(T) error, error) {
+	if s == nil || err != nil || !recorder.Recording() {
+		return s, err
+	}
+
+	key := c.recorderKey(typ + ":" + param)
+
+	return func(val T) error {
+		err := s(val)
+		recorder.Add(recorder.Set, key, val, err)
+		return err
+	}, nil
+}
+
+// replaySetter returns a setter replaying recorded errors. Setters without recording succeed.
+func replaySetter[T any](c *Config, typ, param string) func(T) error {
+	key := c.recorderKey(typ + ":" + param)
+
+	return func(_ T) error {
+		e, err := recorder.Next(recorder.Set, key)
+		if errors.Is(err, recorder.ErrNotRecorded) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = recorder.Decode[T](e)
+		return err
+	}
+}
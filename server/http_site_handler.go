@@ -238,6 +238,13 @@ func stateHandler(cache *util.ParamCache) http.HandlerFunc {
 	}
 }
 
+// batteryStatsHandler returns the battery cycle and degradation statistics
+func batteryStatsHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonWrite(w, site.GetBatteryStats())
+	}
+}
+
 // tariffHandler returns the configured tariff
 func tariffHandler(site site.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -490,3 +497,23 @@ func resetDatabase(authObject auth.Auth, shutdown func()) http.HandlerFunc {
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+// scheduleHandler returns or updates scheduled setting changes
+func scheduleHandler(get func() []api.ScheduledChange, set func([]api.ScheduledChange) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var res []api.ScheduledChange
+			if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+				jsonError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			if err := set(res); err != nil {
+				jsonError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		jsonWrite(w, get())
+	}
+}
@@ -141,6 +141,32 @@ func updatePlanStrategyHandler(site site.API) http.HandlerFunc {
 	}
 }
 
+// departureGuaranteeHandler updates the departure guarantee
+func departureGuaranteeHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		v, err := site.Vehicles().ByName(vars["name"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		var res api.DepartureGuarantee
+		if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := v.SetDepartureGuarantee(res); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonWrite(w, v.GetDepartureGuarantee())
+	}
+}
+
 // addRepeatingPlansHandler handles any information regarding weekday, hour, minute, soc and isActive
 func addRepeatingPlansHandler(site site.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -27,6 +27,7 @@ type Influx struct {
 	client   influxdb2.Client
 	org      string
 	database string
+	sites    sites
 }
 
 // NewInfluxClient creates new publisher for influx
@@ -168,6 +169,11 @@ func (m *Influx) writeComplexPoint(writer pointWriter, key string, val any, tags
 	m.writePoint(writer, key, fields, tags)
 }
 
+// AddSite adds an additional site for resolving loadpoint tags. Must be called before Run.
+func (m *Influx) AddSite(name string, site site.API) {
+	m.sites.add(name, site)
+}
+
 // Run Influx publisher
 func (m *Influx) Run(site site.API, in <-chan util.Param) {
 	writer := m.client.WriteAPI(m.org, m.database)
@@ -183,12 +189,16 @@ func (m *Influx) Run(site site.API, in <-chan util.Param) {
 	// add points to batch for async writing
 	for param := range in {
 		tags := make(map[string]string)
-		if param.Loadpoint != nil {
-			lp := site.Loadpoints()[*param.Loadpoint]
+		if param.Site != "" {
+			tags["site"] = param.Site
+		}
 
-			tags["loadpoint"] = lp.GetTitle()
-			if v := lp.GetVehicle(); v != nil {
-				tags["vehicle"] = v.GetTitle()
+		if param.Loadpoint != nil {
+			if lp, ok := m.sites.loadpoint(site, param.Site, *param.Loadpoint); ok {
+				tags["loadpoint"] = lp.GetTitle()
+				if v := lp.GetVehicle(); v != nil {
+					tags["vehicle"] = v.GetTitle()
+				}
 			}
 		}
 
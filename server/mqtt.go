@@ -189,18 +189,10 @@ func (m *MQTT) publish(topic string, retained bool, payload any) {
 }
 
 func (m *MQTT) Listen(site site.API) error {
-	if err := m.listenSiteSetters(m.root+"/site", site); err != nil {
+	if err := m.listenSite(m.root, site); err != nil {
 		return err
 	}
 
-	// loadpoint setters
-	for id, lp := range site.Loadpoints() {
-		topic := fmt.Sprintf("%s/loadpoints/%d", m.root, id+1)
-		if err := m.listenLoadpointSetters(topic, site, lp); err != nil {
-			return err
-		}
-	}
-
 	// vehicle setters
 	for _, vehicle := range site.Vehicles().Settings() {
 		topic := fmt.Sprintf("%s/vehicles/%s", m.root, vehicle.Name())
@@ -212,6 +204,44 @@ func (m *MQTT) Listen(site site.API) error {
 	return nil
 }
 
+// ListenSite adds setters of an additional site below the site's topic
+func (m *MQTT) ListenSite(name string, site site.API) error {
+	root := m.siteRoot(name)
+
+	// number of loadpoints
+	m.publish(fmt.Sprintf("%s/loadpoints", root), true, len(site.Loadpoints()))
+
+	if err := m.listenSite(root, site); err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+
+	return nil
+}
+
+// siteRoot returns the root topic of the named site, the main root topic if name is empty
+func (m *MQTT) siteRoot(name string) string {
+	if name == "" {
+		return m.root
+	}
+	return fmt.Sprintf("%s/sites/%s", m.root, name)
+}
+
+func (m *MQTT) listenSite(root string, site site.API) error {
+	if err := m.listenSiteSetters(root+"/site", site); err != nil {
+		return err
+	}
+
+	// loadpoint setters
+	for id, lp := range site.Loadpoints() {
+		topic := fmt.Sprintf("%s/loadpoints/%d", root, id+1)
+		if err := m.listenLoadpointSetters(topic, site, lp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *MQTT) listenSiteSetters(topic string, site site.API) error {
 	for _, s := range []setter{
 		{"bufferSoc", floatSetter(site.SetBufferSoc)},
@@ -236,6 +266,7 @@ func (m *MQTT) listenSiteSetters(topic string, site site.API) error {
 			}
 			return site.SetBatteryModeExternal(*m)
 		})},
+		{"schedule", scheduleSetter(site.SetSchedule)},
 	} {
 		if err := m.Handler.ListenSetter(topic+"/"+s.topic, s.fun); err != nil {
 			return err
@@ -263,6 +294,7 @@ func (m *MQTT) listenLoadpointSetters(topic string, site site.API, lp loadpoint.
 		{"batteryBoost", boolSetter(lp.SetBatteryBoost)},
 		{"batteryBoostLimit", intSetter(pass(lp.SetBatteryBoostLimit))},
 		{"planStrategy", planStrategySetter(lp.SetPlanStrategy)},
+		{"schedule", scheduleSetter(lp.SetSchedule)},
 		{"planEnergy", planGoalSetter(lp.SetPlanEnergy)},
 		{"vehicle", func(payload string) error {
 			// https://github.com/evcc-io/evcc/issues/11184 empty payload is swallowed by listener
@@ -291,6 +323,7 @@ func (m *MQTT) listenVehicleSetters(topic string, v vehicle.API) error {
 		{"minSoc", intSetter(pass(v.SetMinSoc))},
 		{"planStrategy", planStrategySetter(v.SetPlanStrategy)},
 		{"planSoc", planGoalSetter(v.SetPlanSoc)},
+		{"departureGuarantee", departureGuaranteeSetter(v.SetDepartureGuarantee)},
 	} {
 		if err := m.Handler.ListenSetter(topic+"/"+s.topic, s.fun); err != nil {
 			return err
@@ -321,14 +354,16 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 
 	// publish
 	for p := range in {
+		root := m.siteRoot(p.Site)
+
 		switch {
 		case p.Loadpoint != nil:
 			id := *p.Loadpoint + 1
-			topic = fmt.Sprintf("%s/loadpoints/%d/%s", m.root, id, p.Key)
+			topic = fmt.Sprintf("%s/loadpoints/%d/%s", root, id, p.Key)
 		case p.Key == "vehicles":
-			topic = fmt.Sprintf("%s/vehicles", m.root)
+			topic = fmt.Sprintf("%s/vehicles", root)
 		default:
-			topic = fmt.Sprintf("%s/site/%s", m.root, p.Key)
+			topic = fmt.Sprintf("%s/site/%s", root, p.Key)
 		}
 
 		// alive indicator
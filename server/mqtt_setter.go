@@ -77,6 +77,28 @@ func planStrategySetter(set func(api.PlanStrategy) error) func(string) error {
 	}
 }
 
+func scheduleSetter(set func([]api.ScheduledChange) error) func(string) error {
+	return func(payload string) error {
+		var res []api.ScheduledChange
+		if err := json.Unmarshal([]byte(payload), &res); err != nil {
+			return err
+		}
+
+		return set(res)
+	}
+}
+
+func departureGuaranteeSetter(set func(api.DepartureGuarantee) error) func(string) error {
+	return func(payload string) error {
+		var res api.DepartureGuarantee
+		if err := json.Unmarshal([]byte(payload), &res); err != nil {
+			return err
+		}
+
+		return set(res)
+	}
+}
+
 func planGoalSetter[T any](set func(time.Time, T) error) func(string) error {
 	return func(payload string) error {
 		var plan planGoal[T]
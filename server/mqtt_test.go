@@ -149,3 +149,9 @@ func (suite *mqttSuite) TestBatteryState() {
 	suite.Equal(topics, suite.topics, "topics")
 	suite.Equal([]string{"2", "", "", "20", "1", "", "", "1", "", "", "", "", "", "10", "", ""}, suite.payloads, "payloads")
 }
+
+func TestMqttSiteRoot(t *testing.T) {
+	m := &MQTT{root: "evcc"}
+	assert.Equal(t, "evcc", m.siteRoot(""))
+	assert.Equal(t, "evcc/sites/garage", m.siteRoot("garage"))
+}
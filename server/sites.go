@@ -0,0 +1,35 @@
+package server
+
+import (
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+)
+
+// sites holds additional sites by name
+type sites map[string]site.API
+
+// add adds an additional site
+func (s *sites) add(name string, site site.API) {
+	if *s == nil {
+		*s = make(sites)
+	}
+	(*s)[name] = site
+}
+
+// loadpoint returns the loadpoint of the named site, using the primary site if name is empty
+func (s sites) loadpoint(primary site.API, name string, id int) (loadpoint.API, bool) {
+	site := primary
+	if name != "" {
+		site = s[name]
+	}
+
+	if site == nil {
+		return nil, false
+	}
+
+	if lps := site.Loadpoints(); id < len(lps) {
+		return lps[id], true
+	}
+
+	return nil, false
+}
@@ -117,10 +117,7 @@ func (h *SocketHub) welcome(subscriber *socketSubscriber, params []util.Param) {
 	sharders := make(map[string]util.Sharder)
 
 	for _, p := range params {
-		k := p.Key
-		if p.Loadpoint != nil {
-			k = "loadpoints." + p.UniqueID()
-		}
+		k := p.Path()
 
 		// Sharder values are split into shards and sent as a separate message
 		if sharder, ok := (p.Val).(util.Sharder); ok {
@@ -156,10 +153,7 @@ func (h *SocketHub) broadcast(p util.Param) {
 
 	msg := make(map[string]json.RawMessage)
 
-	k := p.Key
-	if p.Loadpoint != nil {
-		k = "loadpoints." + p.UniqueID()
-	}
+	k := p.Path()
 
 	// Sharder splits data into chunks
 	if sp, ok := (p.Val).(util.Sharder); ok {
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/messenger"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// WebhookSignatureHeader carries the hex encoded HMAC-SHA256 signature of the request body
+const WebhookSignatureHeader = "X-Evcc-Signature"
+
+// webhookEvents are the loadpoint events published if neither keys nor events are configured
+var webhookEvents = []string{"start", "stop", "connect", "disconnect", "guest"}
+
+// WebhookPayload is the JSON body posted to the webhook url
+type WebhookPayload struct {
+	Type      string    `json:"type"` // value or event
+	Site      string    `json:"site,omitempty"`
+	Loadpoint int       `json:"loadpoint,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Key       string    `json:"key,omitempty"`
+	Event     string    `json:"event,omitempty"`
+	Value     any       `json:"value,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type webhookDebounce struct {
+	sent    time.Time
+	pending *WebhookPayload
+	timer   *clock.Timer
+}
+
+// Webhook is a webhook publisher
+type Webhook struct {
+	*request.Helper
+	mu       sync.Mutex
+	log      *util.Logger
+	clock    clock.Clock
+	uri      string
+	secret   string
+	keys     []string
+	events   []string
+	debounce time.Duration
+	retries  int
+	state    map[string]*webhookDebounce
+	queue    chan WebhookPayload
+	sites    sites
+}
+
+// NewWebhook creates new webhook publisher
+func NewWebhook(uri, secret string, keys, events []string, debounce time.Duration, retries int) (*Webhook, error) {
+	if uri == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	if len(keys) == 0 && len(events) == 0 {
+		events = webhookEvents
+	}
+
+	log := util.NewLogger("webhook")
+
+	m := &Webhook{
+		Helper:   request.NewHelper(log),
+		log:      log,
+		clock:    clock.New(),
+		uri:      uri,
+		secret:   secret,
+		keys:     keys,
+		events:   events,
+		debounce: debounce,
+		retries:  retries,
+		state:    make(map[string]*webhookDebounce),
+		queue:    make(chan WebhookPayload, 128),
+	}
+
+	go m.worker()
+
+	return m, nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 signature of the body
+func (m *Webhook) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(m.secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// enqueue adds the payload to the send queue without blocking the publisher
+func (m *Webhook) enqueue(p WebhookPayload) {
+	select {
+	case m.queue <- p:
+	default:
+		m.log.WARN.Printf("queue full, dropping %s%s", p.Key, p.Event)
+	}
+}
+
+// worker sends queued payloads in order
+func (m *Webhook) worker() {
+	for p := range m.queue {
+		if err := m.send(p); err != nil {
+			m.log.ERROR.Printf("send %s%s: %v", p.Key, p.Event, err)
+		}
+	}
+}
+
+// send posts the payload, retrying on network errors and server-side failures
+func (m *Webhook) send(p WebhookPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	m.log.TRACE.Printf("send %s", body)
+
+	bo := backoff.WithMaxRetries(backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(time.Second),
+		backoff.WithMaxElapsedTime(time.Minute),
+	), uint64(m.retries))
+
+	return backoff.Retry(func() error {
+		req, err := request.New(http.MethodPost, m.uri, bytes.NewReader(body), request.JSONEncoding)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		if m.secret != "" {
+			req.Header.Set(WebhookSignatureHeader, "sha256="+m.Sign(body))
+		}
+
+		_, err = m.DoBody(req)
+
+		// retry rate limits and server errors
+		if se, ok := errors.AsType[*request.StatusError](err); ok && (se.StatusCode() >= 500 || se.HasStatus(http.StatusTooManyRequests)) {
+			return se
+		}
+
+		return err
+	}, bo)
+}
+
+// publish sends the payload, limiting the rate per id to one message per debounce period.
+// Values received during the debounce period are coalesced and the latest one is sent when the period ends.
+func (m *Webhook) publish(id string, p WebhookPayload) {
+	if m.debounce == 0 {
+		m.enqueue(p)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.state[id]
+	if !ok {
+		d = new(webhookDebounce)
+		m.state[id] = d
+	}
+
+	if d.timer == nil && m.clock.Since(d.sent) >= m.debounce {
+		d.sent = m.clock.Now()
+		m.enqueue(p)
+		return
+	}
+
+	d.pending = &p
+
+	if d.timer == nil {
+		d.timer = m.clock.AfterFunc(m.debounce-m.clock.Since(d.sent), func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			if d.pending != nil {
+				d.sent = m.clock.Now()
+				m.enqueue(*d.pending)
+			}
+
+			d.pending = nil
+			d.timer = nil
+		})
+	}
+}
+
+// AddSite adds an additional site for resolving loadpoint titles. Must be called before Run.
+func (m *Webhook) AddSite(name string, site site.API) {
+	m.sites.add(name, site)
+}
+
+// loadpoint adds site name, loadpoint id and title to the payload
+func (m *Webhook) loadpoint(site site.API, p *WebhookPayload, name string, id *int) {
+	p.Site = name
+
+	if id == nil {
+		return
+	}
+
+	p.Loadpoint = *id + 1
+
+	if lp, ok := m.sites.loadpoint(site, name, *id); ok {
+		p.Title = lp.GetTitle()
+	}
+}
+
+// Run Webhook publisher
+func (m *Webhook) Run(site site.API, in <-chan util.Param) {
+	for param := range in {
+		if !slices.Contains(m.keys, param.Key) {
+			continue
+		}
+
+		p := WebhookPayload{
+			Type:      "value",
+			Key:       param.Key,
+			Value:     param.Val,
+			Timestamp: m.clock.Now(),
+		}
+
+		m.loadpoint(site, &p, param.Site, param.Loadpoint)
+		m.publish(param.UniqueID(), p)
+	}
+}
+
+// Events returns an event channel that forwards all events to out and publishes configured loadpoint events
+func (m *Webhook) Events(site site.API, out chan<- messenger.Event) chan messenger.Event {
+	in := make(chan messenger.Event, 1)
+
+	go func() {
+		for ev := range in {
+			out <- ev
+
+			if !slices.Contains(m.events, ev.Event) {
+				continue
+			}
+
+			p := WebhookPayload{
+				Type:      "event",
+				Event:     ev.Event,
+				Timestamp: m.clock.Now(),
+			}
+
+			m.loadpoint(site, &p, ev.Site, ev.Loadpoint)
+
+			// events are never debounced
+			m.enqueue(p)
+		}
+	}()
+
+	return in
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var (
+		body      []byte
+		signature string
+		calls     int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(WebhookSignatureHeader)
+	}))
+	defer srv.Close()
+
+	wh, err := NewWebhook(srv.URL, "secret", []string{"gridPower"}, nil, 0, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, wh.send(WebhookPayload{Type: "value", Key: "gridPower", Value: 1000.0}))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "sha256="+wh.Sign(body), signature)
+
+	var res WebhookPayload
+	require.NoError(t, json.Unmarshal(body, &res))
+	assert.Equal(t, "gridPower", res.Key)
+	assert.Equal(t, 1000.0, res.Value)
+}
+
+func TestWebhookDebounce(t *testing.T) {
+	clock := clock.NewMock()
+
+	wh := &Webhook{
+		clock:    clock,
+		debounce: 10 * time.Second,
+		state:    make(map[string]*webhookDebounce),
+		queue:    make(chan WebhookPayload, 10),
+	}
+
+	wh.publish("foo", WebhookPayload{Value: 1})
+	require.Len(t, wh.queue, 1)
+	assert.Equal(t, 1, (<-wh.queue).Value)
+
+	// coalesced within debounce period
+	wh.publish("foo", WebhookPayload{Value: 2})
+	wh.publish("foo", WebhookPayload{Value: 3})
+	assert.Len(t, wh.queue, 0)
+
+	// other keys are not affected
+	wh.publish("bar", WebhookPayload{Value: 4})
+	require.Len(t, wh.queue, 1)
+	assert.Equal(t, 4, (<-wh.queue).Value)
+
+	// latest value sent when period ends
+	clock.Add(10 * time.Second)
+	require.Len(t, wh.queue, 1)
+	assert.Equal(t, 3, (<-wh.queue).Value)
+}
@@ -11,18 +11,38 @@ import (
 
 // Param is the broadcast channel data type
 type Param struct {
+	Site      string // optional name of additional site
 	Loadpoint *int
 	Key       string
 	Val       any
 }
 
-// UniqueID returns unique identifier for parameter Loadpoint/Key combination
+// UniqueID returns unique identifier for parameter Site/Loadpoint/Key combination
 func (p Param) UniqueID() string {
+	res := p.Key
 	if p.Loadpoint != nil {
-		return strconv.Itoa(*p.Loadpoint) + "." + p.Key
+		res = strconv.Itoa(*p.Loadpoint) + "." + res
 	}
 
-	return p.Key
+	if p.Site != "" {
+		res = p.Site + "." + res
+	}
+
+	return res
+}
+
+// Path returns the parameter's path within the structured state
+func (p Param) Path() string {
+	res := p.Key
+	if p.Loadpoint != nil {
+		res = "loadpoints." + strconv.Itoa(*p.Loadpoint) + "." + res
+	}
+
+	if p.Site != "" {
+		res = "sites." + p.Site + "." + res
+	}
+
+	return res
 }
 
 // ParamCache is a data store
@@ -61,16 +81,38 @@ func (c *ParamCache) Run(in <-chan Param) {
 }
 
 // State provides a structured copy of the cached values.
-// Loadpoints are aggregated as loadpoints array.
+// Loadpoints are aggregated as loadpoints array, additional sites as sites map.
 // Result values are formatted using encoder.
 func (c *ParamCache) State(enc encode.Encoder) map[string]any {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	sites := make(map[string][]Param)
+	for _, param := range c.val {
+		sites[param.Site] = append(sites[param.Site], param)
+	}
+
+	res := state(enc, sites[""])
+
+	if len(sites) > 1 {
+		named := make(map[string]any, len(sites)-1)
+		for name, params := range sites {
+			if name != "" {
+				named[name] = state(enc, params)
+			}
+		}
+		res["sites"] = named
+	}
+
+	return res
+}
+
+// state provides the structured values of a single site
+func state(enc encode.Encoder, params []Param) map[string]any {
 	res := make(map[string]any)
 	lps := make(map[int]map[string]any)
 
-	for _, param := range c.val {
+	for _, param := range params {
 		if param.Loadpoint == nil {
 			res[param.Key] = enc.Encode(param.Val)
 		} else {
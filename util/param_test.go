@@ -3,6 +3,7 @@ package util
 import (
 	"testing"
 
+	"github.com/evcc-io/evcc/util/encode"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,11 +14,42 @@ func TestParam(t *testing.T) {
 		Val: 4711,
 	}
 	assert.Equal(t, "power", p.UniqueID())
+	assert.Equal(t, "power", p.Path())
 
 	p.Loadpoint = &lp
 	assert.Equal(t, "2.power", p.UniqueID())
+	assert.Equal(t, "loadpoints.2.power", p.Path())
+
+	p.Site = "garage"
+	assert.Equal(t, "garage.2.power", p.UniqueID())
+	assert.Equal(t, "sites.garage.loadpoints.2.power", p.Path())
 }
 
 func TestParamCache(t *testing.T) {
 	NewParamCache().Add("foo", Param{})
 }
+
+func TestParamCacheState(t *testing.T) {
+	lp := 0
+	c := NewParamCache()
+
+	for _, p := range []Param{
+		{Key: "gridPower", Val: 1000},
+		{Loadpoint: &lp, Key: "chargePower", Val: 2000},
+		{Site: "garage", Key: "gridPower", Val: 3000},
+		{Site: "garage", Loadpoint: &lp, Key: "chargePower", Val: 4000},
+	} {
+		c.Add(p.UniqueID(), p)
+	}
+
+	assert.Equal(t, map[string]any{
+		"gridPower":  1000,
+		"loadpoints": []map[string]any{{"chargePower": 2000}},
+		"sites": map[string]any{
+			"garage": map[string]any{
+				"gridPower":  3000,
+				"loadpoints": []map[string]any{{"chargePower": 4000}},
+			},
+		},
+	}, c.State(encode.NewEncoder()))
+}
@@ -0,0 +1,73 @@
+package recorder
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type httpValue struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// Transport wraps the base transport for recording or replaying http traffic
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if !Recording() && !Replaying() {
+		return base
+	}
+
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.String()
+
+	if Replaying() {
+		e, err := Next(HTTP, key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := Decode[httpValue](e)
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Response{
+			Status:        http.StatusText(v.Status),
+			StatusCode:    v.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        v.Header,
+			Body:          io.NopCloser(strings.NewReader(v.Body)),
+			ContentLength: int64(len(v.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		Add(HTTP, key, nil, err)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	Add(HTTP, key, httpValue{
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   string(body),
+	}, err)
+
+	return resp, err
+}
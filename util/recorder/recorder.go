@@ -0,0 +1,187 @@
+package recorder
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Kind is the type of recorded traffic
+type Kind string
+
+const (
+	Get  Kind = "get"
+	Set  Kind = "set"
+	HTTP Kind = "http"
+)
+
+// ErrNotRecorded indicates that no recording exists for replay
+var ErrNotRecorded = errors.New("not recorded")
+
+// Entry is a single recorded call
+type Entry struct {
+	Time  time.Time       `json:"time"`
+	Kind  Kind            `json:"kind"`
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// Recorder records calls to a file or replays them from a recording
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	entries map[string][]Entry
+	pos     map[string]int
+}
+
+var instance *Recorder
+
+// Record starts recording to the given file
+func Record(name string) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	instance = &Recorder{
+		file: file,
+	}
+
+	return nil
+}
+
+// Replay loads the recording from the given file for replay
+func Replay(name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := &Recorder{
+		entries: make(map[string][]Entry),
+		pos:     make(map[string]int),
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16<<20)
+
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("invalid recording: %w", err)
+		}
+
+		id := e.id()
+		r.entries[id] = append(r.entries[id], e)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	instance = r
+
+	return nil
+}
+
+// Recording returns true if traffic is recorded
+func Recording() bool {
+	return instance != nil && instance.file != nil
+}
+
+// Replaying returns true if traffic is replayed
+func Replaying() bool {
+	return instance != nil && instance.entries != nil
+}
+
+// Key creates a stable key for the given configuration
+func Key(typ string, conf any) string {
+	// maps are printed with sorted keys
+	sum := sha256.Sum256(fmt.Appendf(nil, "%v", conf))
+	return typ + ":" + hex.EncodeToString(sum[:8])
+}
+
+func (e Entry) id() string {
+	return string(e.Kind) + ":" + e.Key
+}
+
+// Add records a call
+func Add(kind Kind, key string, val any, err error) {
+	if !Recording() {
+		return
+	}
+
+	e := Entry{
+		Time: time.Now(),
+		Kind: kind,
+		Key:  key,
+	}
+
+	if val != nil {
+		e.Value, _ = json.Marshal(val)
+	}
+
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	instance.mu.Lock()
+	defer instance.mu.Unlock()
+
+	_, _ = instance.file.Write(append(b, '\n'))
+}
+
+// Next returns the next recorded entry for replay. Once all entries
+// have been replayed, the last entry is repeated.
+func Next(kind Kind, key string) (Entry, error) {
+	if !Replaying() {
+		return Entry{}, ErrNotRecorded
+	}
+
+	id := Entry{Kind: kind, Key: key}.id()
+
+	instance.mu.Lock()
+	defer instance.mu.Unlock()
+
+	entries := instance.entries[id]
+	if len(entries) == 0 {
+		return Entry{}, fmt.Errorf("%s %s: %w", kind, key, ErrNotRecorded)
+	}
+
+	pos := instance.pos[id]
+	if pos < len(entries)-1 {
+		instance.pos[id] = pos + 1
+	}
+
+	return entries[pos], nil
+}
+
+// Decode returns the entry's value and error
+func Decode[T any](e Entry) (T, error) {
+	var res T
+
+	if len(e.Value) > 0 {
+		if err := json.Unmarshal(e.Value, &res); err != nil {
+			return res, err
+		}
+	}
+
+	if e.Error != "" {
+		return res, errors.New(e.Error)
+	}
+
+	return res, nil
+}
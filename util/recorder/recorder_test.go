@@ -0,0 +1,81 @@
+package recorder
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	t.Cleanup(func() { instance = nil })
+
+	file := filepath.Join(t.TempDir(), "recording.jsonl")
+	require.NoError(t, Record(file))
+
+	key := Key("float", map[string]any{"source": "const", "value": 1})
+	Add(Get, key, 1.0, nil)
+	Add(Get, key, 2.0, nil)
+	Add(Get, key, 0, errors.New("timeout"))
+	Add(Set, key, 3, nil)
+
+	require.NoError(t, Replay(file))
+	assert.False(t, Recording())
+	assert.True(t, Replaying())
+
+	for _, exp := range []float64{1, 2} {
+		e, err := Next(Get, key)
+		require.NoError(t, err)
+		v, err := Decode[float64](e)
+		require.NoError(t, err)
+		assert.Equal(t, exp, v)
+	}
+
+	// last entry is repeated
+	for range 2 {
+		e, err := Next(Get, key)
+		require.NoError(t, err)
+		_, err = Decode[float64](e)
+		assert.EqualError(t, err, "timeout")
+	}
+
+	_, err := Next(Get, "unknown")
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestRecordReplayHTTP(t *testing.T) {
+	t.Cleanup(func() { instance = nil })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	file := filepath.Join(t.TempDir(), "recording.jsonl")
+	require.NoError(t, Record(file))
+
+	client := &http.Client{Transport: Transport(http.DefaultTransport)}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// replay without server
+	srv.Close()
+	require.NoError(t, Replay(file))
+
+	client = &http.Client{Transport: Transport(http.DefaultTransport)}
+
+	resp, err = client.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "hello", string(b))
+}
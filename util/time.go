@@ -8,6 +8,11 @@ import (
 
 // GetNextOccurrence returns the next occurrence of the given time on the specified weekdays.
 func GetNextOccurrence(weekdays []int, timeStr string, tz string) (time.Time, error) {
+	return NextOccurrence(time.Now(), weekdays, timeStr, tz)
+}
+
+// NextOccurrence returns the next occurrence of the given time on the specified weekdays not before now.
+func NextOccurrence(now time.Time, weekdays []int, timeStr string, tz string) (time.Time, error) {
 	loc, err := time.LoadLocation(tz)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("invalid timezone: %w", err)
@@ -20,7 +25,7 @@ func GetNextOccurrence(weekdays []int, timeStr string, tz string) (time.Time, er
 
 	hour, minute := parsedTime.Hour(), parsedTime.Minute()
 
-	now := time.Now().In(loc)
+	now = now.In(loc)
 
 	target := time.Date(
 		now.Year(), now.Month(), now.Day(),